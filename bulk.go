@@ -1,23 +1,17 @@
 package shopify
 
 import (
-	"bufio"
 	"context"
-	"errors"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
-	"reflect"
-	"regexp"
+	"net/http"
 	"time"
 
 	"github.com/goccy/go-json"
-	jsoniter "github.com/json-iterator/go"
 	log "github.com/sirupsen/logrus"
 	"github.com/sogko/go-shopify-graphql/model"
-	"github.com/sogko/go-shopify-graphql/rand"
-	"github.com/sogko/go-shopify-graphql/utils"
+	"github.com/sogko/go-shopify-graphql/schema"
+	"github.com/vinhluan/go-graphql-client"
 	"gopkg.in/guregu/null.v4"
 )
 
@@ -28,18 +22,102 @@ const (
 
 //go:generate mockgen -destination=./mock/bulk_service.go -package=mock . BulkOperationService
 type BulkOperationService interface {
-	BulkQuery(ctx context.Context, query string, v interface{}) error
+	// BulkQuery runs the bulk query and appends its root objects with their nested connections to out, a pointer to
+	// a slice of a struct type. Untyped results are decoded into a *[]*BulkObject or a *[]map[string]interface{}.
+	BulkQuery(ctx context.Context, query string, v interface{}, opts ...BulkQueryOption) error
+	// BulkQueryEach runs the bulk query like BulkQuery, but instead of collecting the results into a slice it calls fn,
+	// a `func(T) error` or `func(*T) error`, with each root object as soon as its nested connections are read.
+	// Returning an error from fn stops reading the results.
+	BulkQueryEach(ctx context.Context, query string, fn interface{}, opts ...BulkQueryOption) error
+	// BulkMutation uploads a JSONL line of variables for every item of inputs and runs the mutation for each of them
+	// with bulkOperationRunMutation. The payloads are decoded into out, a pointer to a slice of the mutation payload
+	// type or nil, at the index of their input, and the returned results hold the errors of every input.
+	BulkMutation(ctx context.Context, mutation string, inputs interface{}, out interface{}) ([]BulkMutationResult, error)
 
 	PostBulkQuery(ctx context.Context, query string) (*string, error)
+	PostBulkMutation(ctx context.Context, mutation string, stagedUploadPath string) (*string, error)
 	GetCurrentBulkQuery(ctx context.Context) (*model.BulkOperation, error)
 	GetCurrentBulkQueryResultURL(ctx context.Context) (*string, error)
-	WaitForCurrentBulkQuery(ctx context.Context, interval time.Duration) (*model.BulkOperation, error)
+	// WaitForCurrentBulkQuery polls the current bulk operation until it's finished, every interval at first and
+	// less often as it runs longer, up to 30s. The interval defaults to 1s when zero.
+	WaitForCurrentBulkQuery(ctx context.Context, interval time.Duration, opts ...BulkQueryOption) (*model.BulkOperation, error)
 	ShouldGetBulkQueryResultURL(ctx context.Context, id *string) (*string, error)
+	// CancelRunningBulkQuery cancels the current bulk query, whichever job started it, and waits for it to stop.
 	CancelRunningBulkQuery(ctx context.Context) error
+	// CancelBulkOperation cancels the bulk operation with the ID and polls it every interval until it's stopped,
+	// returning its final state. An operation that another job started is only cancelled with WithForceCancel.
+	// A zero interval polls adaptively, or every WithWebhookFallback interval with the webhook subscribed.
+	CancelBulkOperation(ctx context.Context, id string, interval time.Duration, opts ...BulkQueryOption) (*model.BulkOperation, error)
+
+	// GetBulkOperation returns the bulk operation with the ID, which doesn't have to be the current one.
+	GetBulkOperation(ctx context.Context, id string) (*model.BulkOperation, error)
+	// WaitForBulkOperation polls the bulk operation with the ID every interval until it's finished. The interval
+	// can be made adaptive and the progress reported with the WithPollInterval and WithProgress options. A zero
+	// interval polls adaptively, or every WithWebhookFallback interval with the webhook subscribed.
+	WaitForBulkOperation(ctx context.Context, id string, interval time.Duration, opts ...BulkQueryOption) (*model.BulkOperation, error)
+	// ResumeBulkQuery waits for the bulk query with the ID, posted earlier possibly by another process, and
+	// decodes its results into out like BulkQuery.
+	ResumeBulkQuery(ctx context.Context, id string, out interface{}, opts ...BulkQueryOption) error
+	// ResumeBulkQueryEach waits for the bulk query with the ID and calls fn with its results like BulkQueryEach.
+	ResumeBulkQueryEach(ctx context.Context, id string, fn interface{}, opts ...BulkQueryOption) error
+	// DownloadBulkQueryResult waits for the bulk query with the ID and writes its JSONL result into w.
+	DownloadBulkQueryResult(ctx context.Context, id string, w io.Writer, opts ...BulkQueryOption) error
+
+	// SubscribeBulkOperationsFinish registers a BULK_OPERATIONS_FINISH webhook delivering to callbackURL, so the bulk
+	// operations are awaited on its deliveries instead of polling.
+	SubscribeBulkOperationsFinish(ctx context.Context, callbackURL string) (*model.WebhookSubscription, error)
+	// BulkOperationsFinishHandler returns the handler of the BULK_OPERATIONS_FINISH deliveries, verified with the
+	// secret of the app.
+	BulkOperationsFinishHandler(secret string) http.Handler
+}
+
+// BulkQueryOption optionally changes how BulkQuery and its variants read the results of the bulk operation.
+type BulkQueryOption func(*bulkQueryOptions)
+
+type bulkQueryOptions struct {
+	partialData     bool
+	progress        func(BulkProgress)
+	minPollInterval time.Duration
+	maxPollInterval time.Duration
+	// pollIntervalSet tells that the caller chose the poll interval, which then takes over the webhook fallback.
+	pollIntervalSet         bool
+	expectedRootObjects     int64
+	webhookFallbackInterval time.Duration
+	forceCancel             bool
+	// finished is called once the operation is no longer running, before its results are read.
+	finished func()
+}
+
+func newBulkQueryOptions(opts []BulkQueryOption) *bulkQueryOptions {
+	options := &bulkQueryOptions{
+		minPollInterval:         defaultMinPollInterval,
+		maxPollInterval:         defaultMaxPollInterval,
+		webhookFallbackInterval: defaultWebhookFallbackInterval,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.maxPollInterval < options.minPollInterval {
+		options.maxPollInterval = options.minPollInterval
+	}
+	if options.webhookFallbackInterval <= 0 {
+		options.webhookFallbackInterval = defaultWebhookFallbackInterval
+	}
+	return options
+}
+
+// WithPartialData reads the partial data of a bulk operation that failed or was canceled, when Shopify provides it.
+// The objects are decoded as usual and a *BulkOperationError with the count of recovered objects is returned.
+func WithPartialData() BulkQueryOption {
+	return func(o *bulkQueryOptions) {
+		o.partialData = true
+	}
 }
 
 type BulkOperationServiceOp struct {
 	client *Client
+	finish bulkFinishNotifier
+	owner  bulkOperationOwner
 }
 
 var _ BulkOperationService = &BulkOperationServiceOp{}
@@ -52,26 +130,29 @@ type mutationBulkOperationRunQueryCancel struct {
 	BulkOperationCancelResult model.BulkOperationCancelPayload `graphql:"bulkOperationCancel(id: $id)" json:"bulkOperationCancel"`
 }
 
-var gidRegex *regexp.Regexp
-
-func init() {
-	gidRegex = regexp.MustCompile(`^gid://shopify/(\w+)/\d+$`)
-}
-
 func (s *BulkOperationServiceOp) PostBulkQuery(ctx context.Context, query string) (*string, error) {
+	err := schema.ValidateBulkQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bulk query: %w", err)
+	}
+
 	m := mutationBulkOperationRunQuery{}
 	vars := map[string]interface{}{
 		"query": null.StringFrom(query),
 	}
 
-	err := s.client.Mutate(ctx, &m, vars)
+	err = s.client.Mutate(ctx, &m, vars)
 	if err != nil {
 		return nil, fmt.Errorf("error posting bulk query: %w", err)
 	}
 	if len(m.BulkOperationRunQueryResult.UserErrors) > 0 {
 		errors, _ := json.MarshalIndent(m.BulkOperationRunQueryResult.UserErrors, "", "    ")
+		if isBulkQueryInProgress(m.BulkOperationRunQueryResult.UserErrors) {
+			return nil, fmt.Errorf("error posting bulk query: %w: %s", ErrBulkQueryInProgress, errors)
+		}
 		return nil, fmt.Errorf("error posting bulk query: %s", errors)
 	}
+	s.owner.own(m.BulkOperationRunQueryResult.BulkOperation.ID)
 
 	return &m.BulkOperationRunQueryResult.BulkOperation.ID, nil
 }
@@ -103,7 +184,10 @@ func (s *BulkOperationServiceOp) ShouldGetBulkQueryResultURL(ctx context.Context
 		return nil, fmt.Errorf("Bulk operation ID doesn't match, got=%v, want=%v", q.ID, id)
 	}
 
-	q, _ = s.WaitForCurrentBulkQuery(ctx, 1*time.Second)
+	q, err = s.WaitForCurrentBulkQuery(ctx, 1*time.Second)
+	if err != nil {
+		return nil, err
+	}
 	if q.Status != model.BulkOperationStatusCompleted {
 		return nil, fmt.Errorf("Bulk operation didn't complete, status=%s, error_code=%s", q.Status, q.ErrorCode)
 	}
@@ -123,24 +207,20 @@ func (s *BulkOperationServiceOp) ShouldGetBulkQueryResultURL(ctx context.Context
 	return q.URL, nil
 }
 
-func (s *BulkOperationServiceOp) WaitForCurrentBulkQuery(ctx context.Context, interval time.Duration) (*model.BulkOperation, error) {
+func (s *BulkOperationServiceOp) WaitForCurrentBulkQuery(ctx context.Context, interval time.Duration, opts ...BulkQueryOption) (*model.BulkOperation, error) {
 	q, err := s.GetCurrentBulkQuery(ctx)
 	if err != nil {
 		return q, fmt.Errorf("CurrentBulkOperation query error: %w", err)
 	}
-
-	for q.Status == model.BulkOperationStatusCreated || q.Status == model.BulkOperationStatusRunning || q.Status == model.BulkOperationStatusCanceling {
-		log.Debugf("Bulk operation is still %s...", q.Status)
-		time.Sleep(interval)
-
-		q, err = s.GetCurrentBulkQuery(ctx)
-		if err != nil {
-			return q, fmt.Errorf("CurrentBulkOperation query error: %w", err)
-		}
+	if q.ID == "" || !isBulkOperationRunning(q) {
+		log.Debugf("Bulk operation ready, latest status=%s", q.Status)
+		return q, nil
 	}
-	log.Debugf("Bulk operation ready, latest status=%s", q.Status)
 
-	return q, nil
+	if interval > 0 {
+		opts = append([]BulkQueryOption{WithPollInterval(interval, defaultMaxPollInterval)}, opts...)
+	}
+	return s.waitForBulkOperation(ctx, q.ID, newBulkQueryOptions(opts))
 }
 
 func (s *BulkOperationServiceOp) CancelRunningBulkQuery(ctx context.Context) error {
@@ -148,325 +228,178 @@ func (s *BulkOperationServiceOp) CancelRunningBulkQuery(ctx context.Context) err
 	if err != nil {
 		return err
 	}
-
-	if q.Status == model.BulkOperationStatusCreated || q.Status == model.BulkOperationStatusRunning {
-		log.Debugln("Canceling running operation")
-		operationID := q.ID
-
-		m := mutationBulkOperationRunQueryCancel{}
-		vars := map[string]interface{}{
-			"id": operationID,
-		}
-
-		err = s.client.Mutate(ctx, &m, vars)
-		if err != nil {
-			return fmt.Errorf("mutation: %w", err)
-		}
-		if len(m.BulkOperationCancelResult.UserErrors) > 0 {
-			return fmt.Errorf("%+v", m.BulkOperationCancelResult.UserErrors)
-		}
-
-		q, err = s.GetCurrentBulkQuery(ctx)
-		if err != nil {
-			return err
-		}
-		for q.Status == model.BulkOperationStatusCreated || q.Status == model.BulkOperationStatusRunning || q.Status == model.BulkOperationStatusCanceling {
-			log.Tracef("Bulk operation still %s...", q.Status)
-			q, err = s.GetCurrentBulkQuery(ctx)
-			if err != nil {
-				return fmt.Errorf("get current bulk query: %w", err)
-			}
-		}
-		log.Debugln("Bulk operation cancelled")
+	if q.ID == "" || !isBulkOperationRunning(q) {
+		return nil
 	}
 
-	return nil
+	_, err = s.cancelBulkOperation(ctx, q.ID, newBulkQueryOptions([]BulkQueryOption{WithForceCancel()}))
+	return err
 }
 
-func (s *BulkOperationServiceOp) BulkQuery(ctx context.Context, query string, out interface{}) error {
-	_, err := s.WaitForCurrentBulkQuery(ctx, 1*time.Second)
+func (s *BulkOperationServiceOp) BulkQuery(ctx context.Context, query string, out interface{}, opts ...BulkQueryOption) error {
+	id, unlock, err := s.startBulkQuery(ctx, query)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	id, err := s.PostBulkQuery(ctx, query)
-	if err != nil {
-		return fmt.Errorf("post bulk query: %w", err)
-	}
-
-	if id == nil {
-		return fmt.Errorf("Posted operation ID is nil")
-	}
-
-	url, err := s.ShouldGetBulkQueryResultURL(ctx, id)
-	if err != nil {
-		return fmt.Errorf("get bulk query result URL: %w", err)
-	}
-
-	if url == nil || *url == "" {
-		// No results
-		return nil
-	}
-
-	filename := fmt.Sprintf("%s%s", rand.String(10), ".jsonl")
-	resultFile := filepath.Join(os.TempDir(), filename)
-	defer os.Remove(resultFile) // Avoid storage overflow in high traffic environments
-	err = utils.DownloadFile(resultFile, *url)
-	if err != nil {
-		return fmt.Errorf("download file: %w", err)
-	}
-
-	err = parseBulkQueryResult(resultFile, out)
-	if err != nil {
-		return fmt.Errorf("parse bulk query result: %w", err)
-	}
-
-	return nil
+	return s.ResumeBulkQuery(ctx, id, out, append(opts, onBulkOperationFinished(unlock))...)
 }
 
-func parseBulkQueryResult(resultFilePath string, out interface{}) error {
-	if reflect.TypeOf(out).Kind() != reflect.Ptr {
-		return fmt.Errorf("the out arg is not a pointer")
-	}
-
-	outValue := reflect.ValueOf(out)
-	outSlice := outValue.Elem()
-	if outSlice.Kind() != reflect.Slice {
-		return fmt.Errorf("the out arg is not a pointer to a slice interface")
-	}
-
-	sliceItemType := outSlice.Type().Elem() // slice item type
-	sliceItemKind := sliceItemType.Kind()
-	itemType := sliceItemType // slice item underlying type
-	if sliceItemKind == reflect.Ptr {
-		itemType = itemType.Elem()
-	}
-
-	resultPath, err := os.Open(resultFilePath)
+func (s *BulkOperationServiceOp) BulkQueryEach(ctx context.Context, query string, fn interface{}, opts ...BulkQueryOption) error {
+	id, unlock, err := s.startBulkQuery(ctx, query)
 	if err != nil {
-		return fmt.Errorf("open file: %w", err)
+		return err
 	}
-	defer utils.CloseFile(resultPath)
-
-	reader := bufio.NewReader(resultPath)
-	json := jsoniter.ConfigFastest
+	defer unlock()
 
-	connectionSink := make(map[string]interface{})
+	return s.ResumeBulkQueryEach(ctx, id, fn, append(opts, onBulkOperationFinished(unlock))...)
+}
 
-	for {
-		var line []byte
-		line, err = reader.ReadBytes('\n')
+func (s *BulkOperationServiceOp) ResumeBulkQuery(ctx context.Context, id string, out interface{}, opts ...BulkQueryOption) error {
+	return s.readBulkQuery(ctx, id, opts, func(r io.Reader) (int, error) {
+		n, err := parseBulkQueryResult(r, out)
 		if err != nil {
-			break
-		}
-
-		parentIDNode := json.Get(line, "__parentId")
-		if parentIDNode.LastError() == nil {
-			parentID := parentIDNode.ToString()
-
-			gid := json.Get(line, "id")
-			if gid.LastError() != nil {
-				return fmt.Errorf("The connection type must query the `id` field")
-			}
-			edgeType, nodeType, connectionFieldName, err := concludeObjectType(gid.ToString())
-			if err != nil {
-				return err
-			}
-			node := reflect.New(nodeType).Interface()
-			err = json.Unmarshal(line, &node)
-			if err != nil {
-				return fmt.Errorf("unmarshalling: %w", err)
-			}
-			nodeVal := reflect.ValueOf(node).Elem()
-
-			var edge interface{}
-			var edgeVal reflect.Value
-			var nodeField reflect.Value
-			if edgeType.Kind() == reflect.Ptr {
-				edge = reflect.New(edgeType.Elem()).Interface()
-				nodeField = reflect.ValueOf(edge).Elem().FieldByName(nodeFieldName)
-				edgeVal = reflect.ValueOf(edge)
-			} else {
-				edge = reflect.New(edgeType).Interface()
-
-				if reflect.ValueOf(edge).Kind() == reflect.Ptr {
-					nodeField = reflect.ValueOf(edge).Elem().FieldByName(nodeFieldName)
-				} else {
-					nodeField = reflect.ValueOf(edge).FieldByName(nodeFieldName)
-				}
-
-				edgeVal = reflect.ValueOf(edge).Elem()
-			}
-
-			if !nodeField.IsValid() {
-				return fmt.Errorf("Edge in the '%s' doesn't have the Node field", connectionFieldName)
-			}
-			nodeField.Set(nodeVal)
-
-			var edgesSlice reflect.Value
-			var edges map[string]interface{}
-			if val, ok := connectionSink[parentID]; ok {
-				var ok2 bool
-				if edges, ok2 = val.(map[string]interface{}); !ok2 {
-					return fmt.Errorf("The connection sink for parent ID '%s' is not a map", parentID)
-				}
-			} else {
-				edges = make(map[string]interface{})
-			}
-
-			if val, ok := edges[connectionFieldName]; ok {
-				edgesSlice = reflect.ValueOf(val)
-			} else {
-				edgesSliceCap := 50
-				edgesSlice = reflect.MakeSlice(reflect.SliceOf(edgeType), 0, edgesSliceCap)
-			}
-
-			edgesSlice = reflect.Append(edgesSlice, edgeVal)
-
-			edges[connectionFieldName] = edgesSlice.Interface()
-			connectionSink[parentID] = edges
-
-			continue
+			return n, fmt.Errorf("parse bulk query result: %w", err)
 		}
+		return n, nil
+	})
+}
 
-		item := reflect.New(itemType).Interface()
-		err = json.Unmarshal(line, &item)
+func (s *BulkOperationServiceOp) ResumeBulkQueryEach(ctx context.Context, id string, fn interface{}, opts ...BulkQueryOption) error {
+	return s.readBulkQuery(ctx, id, opts, func(r io.Reader) (int, error) {
+		n, err := streamBulkQueryResult(r, fn)
 		if err != nil {
-			return fmt.Errorf("unmarshalling: %w", err)
+			return n, fmt.Errorf("stream bulk query result: %w", err)
 		}
-		itemVal := reflect.ValueOf(item)
+		return n, nil
+	})
+}
 
-		if sliceItemKind == reflect.Ptr {
-			outSlice.Set(reflect.Append(outSlice, itemVal))
-		} else {
-			outSlice.Set(reflect.Append(outSlice, itemVal.Elem()))
-		}
-	}
+func (s *BulkOperationServiceOp) DownloadBulkQueryResult(ctx context.Context, id string, w io.Writer, opts ...BulkQueryOption) error {
+	options := newBulkQueryOptions(opts)
 
-	if len(connectionSink) > 0 {
-		err := attachNestedConnections(connectionSink, outSlice)
+	result, err := s.finishedBulkQuery(ctx, id, options)
+	if err != nil {
+		return err
+	}
+	if result.url != "" {
+		err = s.client.downloadBulkResult(ctx, result.url, w, result.size)
 		if err != nil {
-			return fmt.Errorf("error processing nested connections: %w", err)
+			return fmt.Errorf("download file: %w", err)
 		}
 	}
-
-	// check if ReadBytes returned an error different from EOF
-	if err != nil && !errors.Is(err, io.EOF) {
-		return fmt.Errorf("reading the result file: %w", err)
+	if result.failure != nil {
+		return result.failure
 	}
-
 	return nil
 }
 
-func attachNestedConnections(connectionSink map[string]interface{}, outSlice reflect.Value) error {
-	for i := 0; i < outSlice.Len(); i++ {
-		parent := outSlice.Index(i)
-		if parent.Kind() == reflect.Ptr {
-			parent = parent.Elem()
-		}
+// readBulkQuery streams the result of the bulk operation into read as it's downloaded and clears its checkpoint.
+func (s *BulkOperationServiceOp) readBulkQuery(ctx context.Context, id string, opts []BulkQueryOption, read func(r io.Reader) (int, error)) error {
+	options := newBulkQueryOptions(opts)
 
-		nodeField := parent.FieldByName("Node")
-		if nodeField != (reflect.Value{}) {
-			if nodeField.Kind() == reflect.Ptr {
-				parent = nodeField.Elem()
-			} else if nodeField.Kind() == reflect.Interface {
-				parent = nodeField.Elem().Elem()
-			} else {
-				parent = nodeField
+	result, err := s.finishedBulkQuery(ctx, id, options)
+	if err != nil {
+		return err
+	}
+	if result.url != "" {
+		n, err := s.client.streamBulkResult(ctx, result.url, result.size, func(r io.Reader) (int, error) {
+			if result.failure != nil {
+				// The partial data may end in the middle of a line.
+				r = newCompleteLinesReader(r)
 			}
+			return read(r)
+		})
+		if err != nil {
+			return err
 		}
-
-		parentIDField := parent.FieldByName("ID")
-		if parentIDField == (reflect.Value{}) {
-			return fmt.Errorf("No ID field on the first level")
-		}
-		if reflect.TypeOf(parentIDField).Kind() == reflect.Ptr {
-			parentIDField = parentIDField.Elem()
-		}
-
-		var parentID string
-		var ok bool
-		if parentID, ok = parentIDField.Interface().(string); !ok {
-			return fmt.Errorf("ID field on the first level is not a string")
+		if result.failure != nil {
+			result.failure.Recovered = n
 		}
+	}
 
-		var connection interface{}
-		if connection, ok = connectionSink[parentID]; !ok {
-			continue
-		}
+	err = s.clearBulkCheckpoint(ctx, id)
+	if err != nil {
+		return err
+	}
+	if result.failure != nil {
+		return result.failure
+	}
+	return nil
+}
 
-		edgeMap := reflect.ValueOf(connection)
-		iter := edgeMap.MapRange()
-		for iter.Next() {
-			connectionName := iter.Key()
-			connectionField := parent.FieldByName(connectionName.String())
-			if !connectionField.IsValid() {
-				return fmt.Errorf("Connection '%s' is not defined on the parent type %s", connectionName.String(), parent.Type().String())
-			}
+func (s *BulkOperationServiceOp) GetBulkOperation(ctx context.Context, id string) (*model.BulkOperation, error) {
+	var q struct {
+		Node struct {
+			BulkOperation model.BulkOperation `graphql:"... on BulkOperation"`
+		} `graphql:"node(id: $id)"`
+	}
+	vars := map[string]interface{}{
+		"id": graphql.ID(id),
+	}
+	err := s.client.Query(ctx, &q, vars)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	return &q.Node.BulkOperation, nil
+}
 
-			var connectionValue reflect.Value
-			var edgesField reflect.Value
-			if connectionField.Kind() == reflect.Ptr {
-				connectionValue = reflect.ValueOf(reflect.New(connectionField.Type().Elem()).Interface())
-				edgesField = connectionValue.Elem().FieldByName(edgesFieldName)
-			} else {
-				connectionValue = reflect.ValueOf(reflect.New(connectionField.Type()).Interface())
-				edgesField = connectionValue.Elem().FieldByName(edgesFieldName)
-			}
+func (s *BulkOperationServiceOp) WaitForBulkOperation(ctx context.Context, id string, interval time.Duration, opts ...BulkQueryOption) (*model.BulkOperation, error) {
+	if interval > 0 {
+		opts = append([]BulkQueryOption{WithPollInterval(interval, interval)}, opts...)
+	}
+	return s.waitForBulkOperation(ctx, id, newBulkQueryOptions(opts))
+}
 
-			if !edgesField.IsValid() {
-				return fmt.Errorf("Connection %s in the '%s' doesn't have the Edges field", connectionName.String(), parent.Type().String())
-			}
+func isBulkOperationRunning(q *model.BulkOperation) bool {
+	return q.Status == model.BulkOperationStatusCreated || q.Status == model.BulkOperationStatusRunning || q.Status == model.BulkOperationStatusCanceling
+}
 
-			edges := reflect.ValueOf(iter.Value().Interface())
-			edgesField.Set(edges)
+// bulkQueryResult locates the result of a finished bulk operation.
+type bulkQueryResult struct {
+	// url is empty when the operation has no results.
+	url string
+	// size is the size of the result file in bytes, or zero when unknown.
+	size int64
+	// failure describes the operation that didn't complete, with url pointing to its partial data.
+	failure *BulkOperationError
+}
 
-			connectionField.Set(connectionValue)
+// finishedBulkQuery waits for the bulk operation and returns the location of its result. When the operation
+// didn't complete, the error describing the failure is returned, unless its partial data was requested with
+// WithPartialData and is available.
+func (s *BulkOperationServiceOp) finishedBulkQuery(ctx context.Context, id string, options *bulkQueryOptions) (*bulkQueryResult, error) {
+	q, err := s.waitForBulkOperation(ctx, id, options)
+	if err != nil {
+		return nil, err
+	}
+	s.owner.release(id)
+	if options.finished != nil {
+		options.finished()
+	}
+	if q.ID == "" {
+		return nil, fmt.Errorf("Bulk operation %s not found", id)
+	}
 
-			err := attachNestedConnections(connectionSink, iter.Value().Elem())
-			if err != nil {
-				return fmt.Errorf("error attacing a nested connection: %w", err)
-			}
+	if q.Status != model.BulkOperationStatusCompleted || (q.ErrorCode != nil && q.ErrorCode.String() != "") {
+		failure := &BulkOperationError{ID: q.ID, Status: q.Status, ErrorCode: q.ErrorCode}
+		url := q.URL
+		if q.PartialDataURL != nil && *q.PartialDataURL != "" {
+			url = q.PartialDataURL
+		}
+		if !options.partialData || url == nil || *url == "" {
+			return nil, failure
 		}
+		failure.PartialData = true
+		return &bulkQueryResult{url: *url, failure: failure}, nil
 	}
 
-	return nil
-}
-
-func concludeObjectType(gid string) (reflect.Type, reflect.Type, string, error) {
-	submatches := gidRegex.FindStringSubmatch(gid)
-	if len(submatches) != 2 {
-		return reflect.TypeOf(nil), reflect.TypeOf(nil), "", fmt.Errorf("malformed gid=`%s`", gid)
-	}
-	resource := submatches[1]
-	switch resource {
-	case "LineItem":
-		return reflect.TypeOf(model.LineItemEdge{}), reflect.TypeOf(&model.LineItem{}), fmt.Sprintf("%ss", resource), nil
-	case "FulfillmentOrderLineItem":
-		return reflect.TypeOf(model.FulfillmentOrderLineItemEdge{}), reflect.TypeOf(&model.FulfillmentOrderLineItem{}), "LineItems", nil
-	case "FulfillmentOrder":
-		return reflect.TypeOf(model.FulfillmentOrderEdge{}), reflect.TypeOf(&model.FulfillmentOrder{}), fmt.Sprintf("%ss", resource), nil
-	case "MediaImage":
-		return reflect.TypeOf(model.MediaEdge{}), reflect.TypeOf(&model.MediaImage{}), "Media", nil
-	case "Video":
-		return reflect.TypeOf(model.MediaEdge{}), reflect.TypeOf(&model.Video{}), "Media", nil
-	case "Model3d":
-		return reflect.TypeOf(model.MediaEdge{}), reflect.TypeOf(&model.Model3d{}), "Media", nil
-	case "ExternalVideo":
-		return reflect.TypeOf(model.MediaEdge{}), reflect.TypeOf(&model.ExternalVideo{}), "Media", nil
-	case "Metafield":
-		return reflect.TypeOf(model.MetafieldEdge{}), reflect.TypeOf(&model.Metafield{}), fmt.Sprintf("%ss", resource), nil
-	case "Order":
-		return reflect.TypeOf(model.OrderEdge{}), reflect.TypeOf(&model.Order{}), fmt.Sprintf("%ss", resource), nil
-	case "Product":
-		return reflect.TypeOf(model.ProductEdge{}), reflect.TypeOf(&model.Product{}), fmt.Sprintf("%ss", resource), nil
-	case "ProductVariant":
-		return reflect.TypeOf(model.ProductVariantEdge{}), reflect.TypeOf(&model.ProductVariant{}), "Variants", nil
-	case "ProductImage":
-		return reflect.TypeOf(model.ImageEdge{}), reflect.TypeOf(&model.Image{}), "Images", nil
-	case "Collection":
-		return reflect.TypeOf(model.CollectionEdge{}), reflect.TypeOf(&model.Collection{}), "Collections", nil
-	default:
-		return reflect.TypeOf(nil), reflect.TypeOf(nil), "", fmt.Errorf("`%s` not implemented type", resource)
+	if q.ObjectCount == "0" {
+		return &bulkQueryResult{}, nil
+	}
+	if q.URL == nil || *q.URL == "" {
+		return nil, fmt.Errorf("empty URL result")
 	}
+	return &bulkQueryResult{url: *q.URL, size: parseBulkCount(q.FileSize)}, nil
 }
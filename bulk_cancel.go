@@ -0,0 +1,121 @@
+package shopify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/vinhluan/go-graphql-client"
+)
+
+// WithForceCancel lets CancelBulkOperation cancel an operation that another job started.
+func WithForceCancel() BulkQueryOption {
+	return func(o *bulkQueryOptions) {
+		o.forceCancel = true
+	}
+}
+
+// bulkOperationOwner holds the IDs of the bulk operations posted through the service.
+type bulkOperationOwner struct {
+	mu  sync.Mutex
+	ids map[string]struct{}
+}
+
+func (o *bulkOperationOwner) own(id string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.ids == nil {
+		o.ids = make(map[string]struct{})
+	}
+	o.ids[id] = struct{}{}
+}
+
+func (o *bulkOperationOwner) release(id string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.ids, id)
+}
+
+func (o *bulkOperationOwner) owns(id string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, ok := o.ids[id]
+	return ok
+}
+
+// ownsBulkOperation reports whether the operation was posted through the service, or checkpointed by a previous
+// process of the job.
+func (s *BulkOperationServiceOp) ownsBulkOperation(ctx context.Context, id string) (bool, error) {
+	if s.owner.owns(id) {
+		return true, nil
+	}
+
+	store := s.client.bulkCheckpoint
+	if store == nil {
+		return false, nil
+	}
+	checkpoint, err := store.Load(ctx)
+	if err != nil {
+		return false, fmt.Errorf("load bulk checkpoint: %w", err)
+	}
+	return checkpoint != nil && checkpoint.ID == id, nil
+}
+
+func (s *BulkOperationServiceOp) CancelBulkOperation(ctx context.Context, id string, interval time.Duration, opts ...BulkQueryOption) (*model.BulkOperation, error) {
+	if interval > 0 {
+		opts = append([]BulkQueryOption{WithPollInterval(interval, interval)}, opts...)
+	}
+	return s.cancelBulkOperation(ctx, id, newBulkQueryOptions(opts))
+}
+
+func (s *BulkOperationServiceOp) cancelBulkOperation(ctx context.Context, id string, options *bulkQueryOptions) (*model.BulkOperation, error) {
+	q, err := s.GetBulkOperation(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get bulk operation: %w", err)
+	}
+	if q.ID == "" {
+		return nil, fmt.Errorf("Bulk operation %s not found", id)
+	}
+	if !isBulkOperationRunning(q) {
+		s.owner.release(id)
+		return q, nil
+	}
+
+	if !options.forceCancel {
+		owned, err := s.ownsBulkOperation(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !owned {
+			return nil, fmt.Errorf("cancel bulk operation %s: %w", id, ErrBulkOperationNotOwned)
+		}
+	}
+
+	// An operation already CANCELING only has to be waited on.
+	if q.Status != model.BulkOperationStatusCanceling {
+		log.Debugf("Canceling bulk operation %s", id)
+		m := mutationBulkOperationRunQueryCancel{}
+		vars := map[string]interface{}{
+			"id": graphql.ID(id),
+		}
+		err = s.client.Mutate(ctx, &m, vars)
+		if err != nil {
+			return nil, fmt.Errorf("mutation: %w", err)
+		}
+		if len(m.BulkOperationCancelResult.UserErrors) > 0 {
+			return nil, fmt.Errorf("%+v", m.BulkOperationCancelResult.UserErrors)
+		}
+	}
+
+	q, err = s.waitForBulkOperation(ctx, id, options)
+	if err != nil {
+		return q, err
+	}
+	s.owner.release(id)
+	log.Debugf("Bulk operation %s cancelled, latest status=%s", id, q.Status)
+
+	return q, nil
+}
@@ -0,0 +1,83 @@
+package shopify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelBulkOperation(t *testing.T) {
+	id := "gid://shopify/BulkOperation/1"
+	running := model.BulkOperation{ID: id, Status: model.BulkOperationStatusRunning}
+	canceling := model.BulkOperation{ID: id, Status: model.BulkOperationStatusCanceling}
+	canceled := model.BulkOperation{ID: id, Status: model.BulkOperationStatusCanceled}
+	ctx := context.Background()
+
+	t.Run("owned", func(t *testing.T) {
+		gql := &bulkOperationGraphQL{upcoming: []model.BulkOperation{running, canceling, canceled}}
+		s := NewClient("test", WithGraphQLClient(gql)).BulkOperation.(*BulkOperationServiceOp)
+		s.owner.own(id)
+
+		op, err := s.CancelBulkOperation(ctx, id, time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, model.BulkOperationStatusCanceled, op.Status)
+		assert.Equal(t, 1, gql.cancels)
+		assert.False(t, s.owner.owns(id))
+	})
+
+	t.Run("started by another job", func(t *testing.T) {
+		gql := &bulkOperationGraphQL{upcoming: []model.BulkOperation{running}}
+		s := NewClient("test", WithGraphQLClient(gql)).BulkOperation.(*BulkOperationServiceOp)
+
+		_, err := s.CancelBulkOperation(ctx, id, time.Millisecond)
+		assert.ErrorIs(t, err, ErrBulkOperationNotOwned)
+		assert.Equal(t, 0, gql.cancels)
+	})
+
+	t.Run("forced", func(t *testing.T) {
+		gql := &bulkOperationGraphQL{upcoming: []model.BulkOperation{running, canceled}}
+		s := NewClient("test", WithGraphQLClient(gql)).BulkOperation.(*BulkOperationServiceOp)
+
+		op, err := s.CancelBulkOperation(ctx, id, time.Millisecond, WithForceCancel())
+		require.NoError(t, err)
+		assert.Equal(t, model.BulkOperationStatusCanceled, op.Status)
+		assert.Equal(t, 1, gql.cancels)
+	})
+
+	t.Run("checkpointed by a previous process", func(t *testing.T) {
+		store := NewFileBulkCheckpointStore(t.TempDir() + "/checkpoint.json")
+		require.NoError(t, store.Save(ctx, BulkCheckpoint{ID: id, Query: "{ products{ edges{ node{ id } } } }"}))
+		gql := &bulkOperationGraphQL{upcoming: []model.BulkOperation{running, canceled}}
+		s := NewClient("test", WithGraphQLClient(gql), WithBulkCheckpoint(store)).BulkOperation.(*BulkOperationServiceOp)
+
+		_, err := s.CancelBulkOperation(ctx, id, time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, 1, gql.cancels)
+	})
+
+	t.Run("already finished", func(t *testing.T) {
+		completed := model.BulkOperation{ID: id, Status: model.BulkOperationStatusCompleted}
+		gql := &bulkOperationGraphQL{op: completed}
+		s := NewClient("test", WithGraphQLClient(gql)).BulkOperation.(*BulkOperationServiceOp)
+
+		op, err := s.CancelBulkOperation(ctx, id, time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, model.BulkOperationStatusCompleted, op.Status)
+		assert.Equal(t, 0, gql.cancels)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		gql := &bulkOperationGraphQL{op: running}
+		s := NewClient("test", WithGraphQLClient(gql)).BulkOperation.(*BulkOperationServiceOp)
+		s.owner.own(id)
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := s.CancelBulkOperation(ctx, id, time.Hour)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
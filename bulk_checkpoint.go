@@ -0,0 +1,226 @@
+package shopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/goccy/go-json"
+	log "github.com/sirupsen/logrus"
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/sogko/go-shopify-graphql/schema"
+)
+
+// BulkCheckpoint records the bulk query in flight, so a restarted process can resume waiting on it instead of
+// posting the query again.
+type BulkCheckpoint struct {
+	ID        string    `json:"id"`
+	Query     string    `json:"query"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// BulkCheckpointStore persists the BulkCheckpoint of the bulk query in flight. Shopify runs a single bulk query
+// per shop at a time, so the store holds at most one checkpoint.
+type BulkCheckpointStore interface {
+	// Load returns the saved checkpoint, or nil when there is none.
+	Load(ctx context.Context) (*BulkCheckpoint, error)
+	Save(ctx context.Context, checkpoint BulkCheckpoint) error
+	Clear(ctx context.Context) error
+}
+
+// FileBulkCheckpointStore saves the checkpoint as JSON in a file.
+type FileBulkCheckpointStore struct {
+	path string
+}
+
+var _ BulkCheckpointStore = &FileBulkCheckpointStore{}
+
+func NewFileBulkCheckpointStore(path string) *FileBulkCheckpointStore {
+	return &FileBulkCheckpointStore{path: path}
+}
+
+func (s *FileBulkCheckpointStore) Load(ctx context.Context) (*BulkCheckpoint, error) {
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint: %w", err)
+	}
+
+	checkpoint := &BulkCheckpoint{}
+	err = json.Unmarshal(b, checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
+
+func (s *FileBulkCheckpointStore) Save(ctx context.Context, checkpoint BulkCheckpoint) error {
+	b, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("marshalling checkpoint: %w", err)
+	}
+
+	// Write to a temporary file first, so a crash never leaves a truncated checkpoint behind.
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("create checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(b)
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), s.path)
+	if err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (s *FileBulkCheckpointStore) Clear(ctx context.Context) error {
+	err := os.Remove(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove checkpoint: %w", err)
+	}
+	return nil
+}
+
+// startBulkQuery returns the ID of the bulk operation running the query, holding the bulk query lock of the shop
+// until unlock is called. With a checkpoint store, an operation of the same query left by a previous process is
+// resumed, otherwise the query is posted once the operations of other processes are finished, and checkpointed.
+func (s *BulkOperationServiceOp) startBulkQuery(ctx context.Context, query string) (id string, unlock func(), err error) {
+	// Validate before waiting on the current operation, which may take hours to finish.
+	err = schema.ValidateBulkQuery(query)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid bulk query: %w", err)
+	}
+
+	release, err := s.lockBulkQuery(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+
+	store := s.client.bulkCheckpoint
+	if store != nil {
+		checkpoint, err := store.Load(ctx)
+		if err != nil {
+			return "", nil, fmt.Errorf("load bulk checkpoint: %w", err)
+		}
+		if checkpoint != nil && checkpoint.Query == query {
+			resumable, err := s.isResumable(ctx, checkpoint.ID)
+			if err != nil {
+				return "", nil, err
+			}
+			if resumable {
+				return checkpoint.ID, release, nil
+			}
+		}
+	}
+
+	posted, err := s.postBulkQueryWhenIdle(ctx, query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if store != nil {
+		err = store.Save(ctx, BulkCheckpoint{ID: posted, Query: query, StartedAt: time.Now()})
+		if err != nil {
+			return "", nil, fmt.Errorf("save bulk checkpoint: %w", err)
+		}
+	}
+
+	return posted, release, nil
+}
+
+// postBulkQueryWhenIdle waits for the current operation to finish before posting the query. The lock doesn't cover
+// the processes that don't share it, so the current operation is waited on again when one of theirs got in first.
+func (s *BulkOperationServiceOp) postBulkQueryWhenIdle(ctx context.Context, query string) (string, error) {
+	for {
+		current, err := s.GetCurrentBulkQuery(ctx)
+		if err != nil {
+			return "", fmt.Errorf("get current bulk operation: %w", err)
+		}
+		if current.ID != "" && isBulkOperationRunning(current) {
+			log.Debugf("Waiting for the current bulk operation %s to finish", current.ID)
+			_, err = s.waitForBulkOperation(ctx, current.ID, newBulkQueryOptions(nil))
+			if err != nil {
+				return "", err
+			}
+		}
+
+		id, err := s.PostBulkQuery(ctx, query)
+		if errors.Is(err, ErrBulkQueryInProgress) {
+			log.Debugln("Another bulk query is in progress, waiting for it to finish")
+			timer := time.NewTimer(bulkQueryInProgressWait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return "", ctx.Err()
+			case <-timer.C:
+			}
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("post bulk query: %w", err)
+		}
+		if id == nil {
+			return "", fmt.Errorf("Posted operation ID is nil")
+		}
+		return *id, nil
+	}
+}
+
+// isResumable reports whether the checkpointed operation is still running or has completed with results that
+// can be downloaded.
+func (s *BulkOperationServiceOp) isResumable(ctx context.Context, id string) (bool, error) {
+	op, err := s.GetBulkOperation(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("get checkpointed bulk operation: %w", err)
+	}
+	if op.ID == "" {
+		return false, nil
+	}
+	if op.Status == model.BulkOperationStatusCompleted {
+		return op.URL != nil || op.ObjectCount == "0", nil
+	}
+	return isBulkOperationRunning(op), nil
+}
+
+// clearBulkCheckpoint removes the checkpoint of the operation once its results have been consumed.
+func (s *BulkOperationServiceOp) clearBulkCheckpoint(ctx context.Context, id string) error {
+	store := s.client.bulkCheckpoint
+	if store == nil {
+		return nil
+	}
+
+	checkpoint, err := store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("load bulk checkpoint: %w", err)
+	}
+	if checkpoint == nil || checkpoint.ID != id {
+		return nil
+	}
+
+	err = store.Clear(ctx)
+	if err != nil {
+		return fmt.Errorf("clear bulk checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package shopify
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/sogko/go-shopify-graphql/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vinhluan/go-graphql-client"
+)
+
+// bulkOperationGraphQL answers every bulk operation query with op, after moving it to the next of the upcoming
+// operations if any, and every bulkOperationRunQuery with postedID.
+type bulkOperationGraphQL struct {
+	graphql.GraphQL
+	op       model.BulkOperation
+	upcoming []model.BulkOperation
+	postedID string
+	posts    int
+	cancels  int
+}
+
+func (g *bulkOperationGraphQL) Query(ctx context.Context, q interface{}, variables map[string]interface{}) (*graphql.Result, error) {
+	if len(g.upcoming) > 0 {
+		g.op, g.upcoming = g.upcoming[0], g.upcoming[1:]
+	}
+	v := reflect.ValueOf(q).Elem()
+	if node := v.FieldByName("Node"); node.IsValid() {
+		node.FieldByName("BulkOperation").Set(reflect.ValueOf(g.op))
+	} else {
+		v.FieldByName("CurrentBulkOperation").Field(0).Set(reflect.ValueOf(g.op))
+	}
+	return &graphql.Result{}, nil
+}
+
+func (g *bulkOperationGraphQL) Mutate(ctx context.Context, m interface{}, variables map[string]interface{}) (*graphql.Result, error) {
+	if _, ok := m.(*mutationBulkOperationRunQueryCancel); ok {
+		g.cancels++
+		return &graphql.Result{}, nil
+	}
+	g.posts++
+	m.(*mutationBulkOperationRunQuery).BulkOperationRunQueryResult.BulkOperation = &model.BulkOperation{ID: g.postedID}
+	return &graphql.Result{}, nil
+}
+
+func TestFileBulkCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileBulkCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+
+	checkpoint, err := store.Load(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	saved := BulkCheckpoint{ID: "gid://shopify/BulkOperation/1", Query: "{ shop{ id } }", StartedAt: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)}
+	require.NoError(t, store.Save(ctx, saved))
+	checkpoint, err = store.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &saved, checkpoint)
+
+	require.NoError(t, store.Clear(ctx))
+	require.NoError(t, store.Clear(ctx))
+	checkpoint, err = store.Load(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, checkpoint)
+}
+
+func TestStartBulkQueryResumesCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	query := "{ products{ edges{ node{ id } } } }"
+	checkpointed := "gid://shopify/BulkOperation/1"
+
+	tests := []struct {
+		name   string
+		query  string
+		status model.BulkOperationStatus
+		want   string
+	}{{
+		name:   "running operation is resumed",
+		query:  query,
+		status: model.BulkOperationStatusRunning,
+		want:   checkpointed,
+	}, {
+		name:   "failed operation is posted again",
+		query:  query,
+		status: model.BulkOperationStatusFailed,
+		want:   "gid://shopify/BulkOperation/2",
+	}, {
+		name:   "operation of another query is not resumed",
+		query:  "{ orders{ edges{ node{ id } } } }",
+		status: model.BulkOperationStatusRunning,
+		want:   "gid://shopify/BulkOperation/2",
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewFileBulkCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+			require.NoError(t, store.Save(ctx, BulkCheckpoint{ID: checkpointed, Query: tt.query, StartedAt: time.Now()}))
+
+			gql := &bulkOperationGraphQL{
+				op:       model.BulkOperation{ID: checkpointed, Status: tt.status},
+				postedID: "gid://shopify/BulkOperation/2",
+			}
+			if tt.query != query {
+				// The current operation must be finished before another query can be posted.
+				gql.op.Status = model.BulkOperationStatusCompleted
+			}
+			client := NewClient("test", WithGraphQLClient(gql), WithBulkCheckpoint(store))
+
+			id, unlock, err := client.BulkOperation.(*BulkOperationServiceOp).startBulkQuery(ctx, query)
+			require.NoError(t, err)
+			defer unlock()
+			assert.Equal(t, tt.want, id)
+
+			checkpoint, err := store.Load(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, checkpoint.ID)
+			assert.Equal(t, query, checkpoint.Query)
+
+			require.NoError(t, client.BulkOperation.(*BulkOperationServiceOp).clearBulkCheckpoint(ctx, id))
+			checkpoint, err = store.Load(ctx)
+			require.NoError(t, err)
+			assert.Nil(t, checkpoint)
+		})
+	}
+}
+
+func TestBulkQueryValidatesQueryBeforePosting(t *testing.T) {
+	gql := &bulkOperationGraphQL{
+		op:       model.BulkOperation{Status: model.BulkOperationStatusCompleted},
+		postedID: "gid://shopify/BulkOperation/1",
+	}
+	client := NewClient("test", WithGraphQLClient(gql))
+
+	var res []*model.Product
+	err := client.BulkOperation.BulkQuery(context.Background(), `{ products{ edges{ node{ title variants{ edges{ node{ sku } } } } } } }`, &res)
+	var errs schema.Errors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+	assert.Equal(t, []string{"products", "edges", "node"}, errs[0].Path)
+	assert.Equal(t, []string{"products", "edges", "node", "variants", "edges", "node"}, errs[1].Path)
+	assert.Zero(t, gql.posts)
+
+	_, err = client.BulkOperation.PostBulkQuery(context.Background(), `{ node(id: "gid://shopify/Product/1"){ id } }`)
+	assert.EqualError(t, err, "invalid bulk query: 1:3: node: bulk queries don't support the top level `node` field, query a connection instead")
+	assert.Zero(t, gql.posts)
+}
@@ -0,0 +1,170 @@
+package shopify
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const defaultDownloadRetries = 3
+
+// downloadRetryWait is the wait before the first retry of a download, growing linearly with every retry.
+var downloadRetryWait = 1 * time.Second
+
+var contentRangeRegex = regexp.MustCompile(`^bytes (\d+)-\d+/(?:\d+|\*)$`)
+
+// errDownloadStatus is returned for a response status that retrying won't change, e.g. an expired URL.
+var errDownloadStatus = errors.New("unexpected status")
+
+// downloadWriter records the errors of the destination, which stop the download instead of being retried.
+type downloadWriter struct {
+	w       io.Writer
+	written int64
+	err     error
+}
+
+func (d *downloadWriter) Write(p []byte) (int, error) {
+	n, err := d.w.Write(p)
+	d.written += int64(n)
+	if err != nil {
+		d.err = err
+	}
+	return n, err
+}
+
+// downloadBulkResult writes the file at url into w. An interrupted download is retried, resuming with a Range
+// request after the bytes already written. When expectedSize is known, the count of bytes written is checked.
+func (c *Client) downloadBulkResult(ctx context.Context, url string, w io.Writer, expectedSize int64) error {
+	retries := c.retries
+	if retries <= 0 {
+		retries = defaultDownloadRetries
+	}
+	httpClient := &http.Client{Transport: c.transport}
+	dw := &downloadWriter{w: w}
+
+	for attempt := 0; ; attempt++ {
+		err := downloadBulkResultFrom(ctx, httpClient, url, dw)
+		if err == nil {
+			break
+		}
+		if dw.err != nil || errors.Is(err, errDownloadStatus) || ctx.Err() != nil || attempt >= retries {
+			return err
+		}
+
+		wait := time.Duration(attempt+1) * downloadRetryWait
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if expectedSize > 0 && dw.written != expectedSize {
+		return fmt.Errorf("downloaded %d bytes, expected %d", dw.written, expectedSize)
+	}
+	return nil
+}
+
+// downloadBulkResultFrom requests the file after the bytes already written into dw and copies the rest of it.
+func downloadBulkResultFrom(ctx context.Context, httpClient *http.Client, url string, dw *downloadWriter) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	if dw.written > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", dw.written))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("get: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The server may ignore the range, in which case the bytes already written are skipped.
+	var skip int64
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		skip = dw.written
+	case resp.StatusCode == http.StatusPartialContent:
+		submatches := contentRangeRegex.FindStringSubmatch(resp.Header.Get("Content-Range"))
+		if len(submatches) != 2 {
+			return fmt.Errorf("malformed Content-Range `%s`", resp.Header.Get("Content-Range"))
+		}
+		start, _ := strconv.ParseInt(submatches[1], 10, 64)
+		if start > dw.written {
+			return fmt.Errorf("Content-Range starts at %d, after the %d bytes downloaded", start, dw.written)
+		}
+		skip = dw.written - start
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("get: status %s", resp.Status)
+	default:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("get: %w %s: %s", errDownloadStatus, resp.Status, msg)
+	}
+
+	body := io.Reader(resp.Body)
+	if resp.Header.Get("Content-Encoding") == "gzip" && !resp.Uncompressed {
+		if resp.StatusCode == http.StatusPartialContent && skip != dw.written {
+			return fmt.Errorf("%w: a range of a gzip encoded file can't be decompressed", errDownloadStatus)
+		}
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("gzip: %w", err)
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	if skip > 0 {
+		_, err = io.CopyN(io.Discard, body, skip)
+		if err != nil {
+			return fmt.Errorf("skip the %d bytes downloaded: %w", skip, err)
+		}
+	}
+
+	_, err = io.Copy(dw, body)
+	if err != nil {
+		return fmt.Errorf("read body: %w", err)
+	}
+	return nil
+}
+
+// streamBulkResult downloads the file at url and passes its content to read as it's being downloaded.
+func (c *Client) streamBulkResult(ctx context.Context, url string, expectedSize int64, read func(r io.Reader) (int, error)) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	downloaded := make(chan error, 1)
+	go func() {
+		err := c.downloadBulkResult(ctx, url, pw, expectedSize)
+		pw.CloseWithError(err)
+		downloaded <- err
+	}()
+
+	n, err := read(pr)
+	// Stop the download when read returns early, e.g. on an error.
+	pr.CloseWithError(io.ErrClosedPipe)
+	cancel()
+	downloadErr := <-downloaded
+
+	if err != nil {
+		if downloadErr != nil && !errors.Is(downloadErr, io.ErrClosedPipe) && !errors.Is(downloadErr, context.Canceled) {
+			return n, fmt.Errorf("download file: %w", downloadErr)
+		}
+		return n, err
+	}
+	if downloadErr != nil {
+		return n, fmt.Errorf("download file: %w", downloadErr)
+	}
+	return n, nil
+}
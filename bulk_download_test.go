@@ -0,0 +1,147 @@
+package shopify
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	downloadRetryWait = time.Millisecond
+}
+
+// interruptedHandler sends the first half of content and drops the connection on the first request, then
+// answers the retries with resume.
+func interruptedHandler(t *testing.T, content string, resume http.HandlerFunc) (http.HandlerFunc, *[]string) {
+	var ranges []string
+	return func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if len(ranges) > 1 {
+			resume(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(content[:len(content)/2]))
+		w.(http.Flusher).Flush()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		conn.Close()
+	}, &ranges
+}
+
+func TestDownloadBulkResult(t *testing.T) {
+	content := bulkResultFixture
+	half := len(content) / 2
+
+	tests := []struct {
+		name   string
+		resume http.HandlerFunc
+	}{{
+		name: "range resumed",
+		resume: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", half, len(content)-1, len(content)))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write([]byte(content[half:]))
+		},
+	}, {
+		name: "range ignored",
+		resume: func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(content))
+		},
+	}, {
+		name: "range ignored with gzip encoding",
+		resume: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			gz.Write([]byte(content))
+			gz.Close()
+		},
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			handler, ranges := interruptedHandler(t, content, tt.resume)
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+
+			var buf bytes.Buffer
+			client := NewClient("test")
+			require.NoError(t, client.downloadBulkResult(context.Background(), srv.URL, &buf, int64(len(content))))
+			assert.Equal(t, content, buf.String())
+			assert.Equal(t, []string{"", fmt.Sprintf("bytes=%d-", half)}, *ranges)
+		})
+	}
+}
+
+func TestDownloadBulkResultErrors(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/expired" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<Error>ExpiredToken</Error>"))
+			return
+		}
+		w.Write([]byte("{}\n"))
+	}))
+	defer srv.Close()
+	client := NewClient("test")
+
+	var buf bytes.Buffer
+	err := client.downloadBulkResult(context.Background(), srv.URL+"/expired", &buf, 0)
+	assert.EqualError(t, err, "get: unexpected status 403 Forbidden: <Error>ExpiredToken</Error>")
+	assert.Equal(t, 1, requests)
+	assert.Zero(t, buf.Len())
+
+	err = client.downloadBulkResult(context.Background(), srv.URL, &buf, 10)
+	assert.EqualError(t, err, "downloaded 3 bytes, expected 10")
+}
+
+func TestDownloadBulkQueryResult(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(bulkResultFixture))
+	}))
+	defer srv.Close()
+
+	fileSize := strconv.Itoa(len(bulkResultFixture))
+	gql := &bulkOperationGraphQL{op: model.BulkOperation{
+		ID:          "gid://shopify/BulkOperation/1",
+		Status:      model.BulkOperationStatusCompleted,
+		ObjectCount: "7",
+		FileSize:    &fileSize,
+		URL:         &srv.URL,
+	}}
+	client := NewClient("test", WithGraphQLClient(gql))
+
+	var buf bytes.Buffer
+	require.NoError(t, client.BulkOperation.DownloadBulkQueryResult(context.Background(), gql.op.ID, &buf))
+	assert.Equal(t, bulkResultFixture, buf.String())
+
+	var titles []string
+	err := client.BulkOperation.ResumeBulkQueryEach(context.Background(), gql.op.ID, func(p model.Product) error {
+		titles = append(titles, p.Title)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Shirt", "Hat", "Socks"}, titles)
+
+	errStop := fmt.Errorf("stop")
+	err = client.BulkOperation.ResumeBulkQueryEach(context.Background(), gql.op.ID, func(p model.Product) error {
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.True(t, strings.HasPrefix(err.Error(), "stream bulk query result: "), err.Error())
+}
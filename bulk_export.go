@@ -0,0 +1,246 @@
+package shopify
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const defaultBulkExportSeparator = "|"
+
+// BulkExportColumn is a column of the export of a bulk query result, the value at Path of the root objects or of
+// the objects nested in them.
+type BulkExportColumn struct {
+	// Name is the header of the column in CSV and the key of the value in NDJSON.
+	Name string
+	// Child is the dot separated chain of typenames leading from the root object to the nested objects the value
+	// is read from, e.g. `LineItem` for the line items of orders or `LineItem.Metafield` for their metafields.
+	// It's empty for the root object.
+	Child string
+	// Path is the dot separated path of the value within the object, e.g. `totalPriceSet.shopMoney.amount`.
+	// The items of a list are addressed by their index, e.g. `tags.0`.
+	Path string
+}
+
+// BulkExportOption optionally changes how the result of a bulk query is exported.
+type BulkExportOption func(*bulkExportOptions)
+
+type bulkExportOptions struct {
+	rowChild  []string
+	separator string
+}
+
+// WithRowPerChild writes a row for every nested object of the child chain, e.g. `LineItem`, repeating the values
+// of its root object. A root object without any such child is written on a single row.
+func WithRowPerChild(child string) BulkExportOption {
+	return func(o *bulkExportOptions) {
+		o.rowChild = splitBulkPath(child)
+	}
+}
+
+// WithJoinSeparator sets the separator of the values of several nested objects joined in a CSV cell, `|` by default.
+func WithJoinSeparator(separator string) BulkExportOption {
+	return func(o *bulkExportOptions) {
+		o.separator = separator
+	}
+}
+
+type bulkExportColumn struct {
+	name  string
+	child []string
+	path  []string
+}
+
+// bulkExporter turns every root object into rows of values, a value being a list for the columns of several
+// nested objects.
+type bulkExporter struct {
+	columns []bulkExportColumn
+	options *bulkExportOptions
+}
+
+func newBulkExporter(columns []BulkExportColumn, opts []BulkExportOption) (*bulkExporter, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns to export")
+	}
+	options := &bulkExportOptions{separator: defaultBulkExportSeparator}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	e := &bulkExporter{options: options}
+	for _, c := range columns {
+		if c.Path == "" {
+			return nil, fmt.Errorf("column `%s` has no path", c.Name)
+		}
+		e.columns = append(e.columns, bulkExportColumn{name: c.Name, child: splitBulkPath(c.Child), path: splitBulkPath(c.Path)})
+	}
+	return e, nil
+}
+
+func (e *bulkExporter) rows(root *BulkObject) [][]interface{} {
+	if len(e.options.rowChild) == 0 {
+		return [][]interface{}{e.row(root, nil)}
+	}
+
+	children := root.descendants(e.options.rowChild)
+	if len(children) == 0 {
+		return [][]interface{}{e.row(root, nil)}
+	}
+	rows := make([][]interface{}, len(children))
+	for i, child := range children {
+		rows[i] = e.row(root, child)
+	}
+	return rows
+}
+
+// row reads the values of the columns from the root object, or from the row child for the columns of its chain.
+func (e *bulkExporter) row(root *BulkObject, rowChild *BulkObject) []interface{} {
+	row := make([]interface{}, len(e.columns))
+	for i, c := range e.columns {
+		base, chain := root, c.child
+		if hasBulkPathPrefix(c.child, e.options.rowChild) {
+			if rowChild == nil {
+				continue
+			}
+			base, chain = rowChild, c.child[len(e.options.rowChild):]
+		}
+
+		if len(chain) == 0 {
+			row[i] = base.value(c.path)
+			continue
+		}
+		objects := base.descendants(chain)
+		values := make([]interface{}, len(objects))
+		for j, obj := range objects {
+			values[j] = obj.value(c.path)
+		}
+		row[i] = values
+	}
+	return row
+}
+
+// ExportBulkResultCSV writes the JSONL result of a bulk query as CSV, with a header row of the column names, and
+// returns the count of rows written. The values of several nested objects are joined in a cell, unless a row is
+// written per nested object with WithRowPerChild. The result is streamed, a root object at a time.
+func ExportBulkResultCSV(r io.Reader, w io.Writer, columns []BulkExportColumn, opts ...BulkExportOption) (int, error) {
+	e, err := newBulkExporter(columns, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	cw := csv.NewWriter(w)
+	header := make([]string, len(e.columns))
+	for i, c := range e.columns {
+		header[i] = c.name
+	}
+	err = cw.Write(header)
+	if err != nil {
+		return 0, fmt.Errorf("write CSV: %w", err)
+	}
+
+	count, err := e.export(r, func(row []interface{}) error {
+		record := make([]string, len(row))
+		for i, v := range row {
+			record[i] = e.formatCSV(v)
+		}
+		return cw.Write(record)
+	})
+	cw.Flush()
+	if err == nil {
+		err = cw.Error()
+	}
+	return count, err
+}
+
+// ExportBulkResultNDJSON writes the JSONL result of a bulk query as NDJSON, a flat object keyed by the column names
+// per row, and returns the count of rows written. The values of several nested objects are written as a list,
+// unless a row is written per nested object with WithRowPerChild. The result is streamed, a root object at a time.
+func ExportBulkResultNDJSON(r io.Reader, w io.Writer, columns []BulkExportColumn, opts ...BulkExportOption) (int, error) {
+	e, err := newBulkExporter(columns, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	stream := bulkObjectJSON.BorrowStream(w)
+	defer bulkObjectJSON.ReturnStream(stream)
+
+	return e.export(r, func(row []interface{}) error {
+		stream.WriteObjectStart()
+		for i, v := range row {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			stream.WriteObjectField(e.columns[i].name)
+			stream.WriteVal(v)
+		}
+		stream.WriteObjectEnd()
+		stream.WriteRaw("\n")
+		if stream.Error != nil {
+			return stream.Error
+		}
+		return stream.Flush()
+	})
+}
+
+func (e *bulkExporter) export(r io.Reader, write func(row []interface{}) error) (int, error) {
+	objects := newBulkObjectReader(r)
+	count := 0
+	for {
+		root, err := objects.Read()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+
+		for _, row := range e.rows(root) {
+			err = write(row)
+			if err != nil {
+				return count, fmt.Errorf("write row: %w", err)
+			}
+			count++
+		}
+	}
+}
+
+func (e *bulkExporter) formatCSV(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, item := range v {
+			values[i] = e.formatCSV(item)
+		}
+		return strings.Join(values, e.options.separator)
+	case map[string]interface{}:
+		b, _ := bulkObjectJSON.Marshal(v)
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func splitBulkPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+func hasBulkPathPrefix(path, prefix []string) bool {
+	if len(prefix) == 0 || len(path) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package shopify
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bulkExportFixture = `{"id":"gid://shopify/Order/1","name":"#1001","totalPriceSet":{"shopMoney":{"amount":"30.00"}},"tags":["vip","wholesale"]}
+{"id":"gid://shopify/LineItem/11","sku":"SHIRT-S","quantity":2,"__parentId":"gid://shopify/Order/1"}
+{"id":"gid://shopify/Metafield/111","key":"color","value":"red","__parentId":"gid://shopify/LineItem/11"}
+{"id":"gid://shopify/LineItem/12","sku":"HAT","quantity":1,"__parentId":"gid://shopify/Order/1"}
+{"id":"gid://shopify/Order/2","name":"#1002","totalPriceSet":{"shopMoney":{"amount":"5.50"}},"tags":[]}
+`
+
+var bulkExportColumns = []BulkExportColumn{
+	{Name: "order", Path: "name"},
+	{Name: "total", Path: "totalPriceSet.shopMoney.amount"},
+	{Name: "first_tag", Path: "tags.0"},
+	{Name: "sku", Child: "LineItem", Path: "sku"},
+	{Name: "quantity", Child: "LineItem", Path: "quantity"},
+	{Name: "color", Child: "LineItem.Metafield", Path: "value"},
+}
+
+func TestExportBulkResultCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []BulkExportOption
+		want string
+		rows int
+	}{{
+		name: "children joined",
+		opts: []BulkExportOption{WithJoinSeparator(";")},
+		want: `order,total,first_tag,sku,quantity,color
+#1001,30.00,vip,SHIRT-S;HAT,2;1,red
+#1002,5.50,,,,
+`,
+		rows: 2,
+	}, {
+		name: "row per child",
+		opts: []BulkExportOption{WithRowPerChild("LineItem")},
+		want: `order,total,first_tag,sku,quantity,color
+#1001,30.00,vip,SHIRT-S,2,red
+#1001,30.00,vip,HAT,1,
+#1002,5.50,,,,
+`,
+		rows: 3,
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			rows, err := ExportBulkResultCSV(strings.NewReader(bulkExportFixture), &buf, bulkExportColumns, tt.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.rows, rows)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestExportBulkResultNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	rows, err := ExportBulkResultNDJSON(strings.NewReader(bulkExportFixture), &buf, bulkExportColumns)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rows)
+	assert.Equal(t, `{"order":"#1001","total":"30.00","first_tag":"vip","sku":["SHIRT-S","HAT"],"quantity":[2,1],"color":["red"]}
+{"order":"#1002","total":"5.50","first_tag":null,"sku":[],"quantity":[],"color":[]}
+`, buf.String())
+
+	buf.Reset()
+	rows, err = ExportBulkResultNDJSON(strings.NewReader(bulkExportFixture), &buf, bulkExportColumns, WithRowPerChild("LineItem"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, rows)
+	assert.Equal(t, `{"order":"#1001","total":"30.00","first_tag":"vip","sku":"SHIRT-S","quantity":2,"color":["red"]}
+{"order":"#1001","total":"30.00","first_tag":"vip","sku":"HAT","quantity":1,"color":[]}
+{"order":"#1002","total":"5.50","first_tag":null,"sku":null,"quantity":null,"color":null}
+`, buf.String())
+}
+
+func TestExportBulkResultErrors(t *testing.T) {
+	_, err := ExportBulkResultCSV(strings.NewReader(bulkExportFixture), &bytes.Buffer{}, nil)
+	assert.EqualError(t, err, "no columns to export")
+
+	_, err = ExportBulkResultCSV(strings.NewReader(`{"id":"gid://shopify/LineItem/1","__parentId":"gid://shopify/Order/1"}`), &bytes.Buffer{}, bulkExportColumns)
+	assert.EqualError(t, err, "object with __parentId `gid://shopify/Order/1` precedes its parent")
+}
@@ -0,0 +1,149 @@
+package shopify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sogko/go-shopify-graphql/model"
+)
+
+// defaultBulkQueryLock serialises the bulk queries of the clients of the process that don't set their own lock.
+var defaultBulkQueryLock = NewMemoryBulkQueryLock()
+
+// bulkQueryInProgressWait is the wait before posting a query again when another process got its query in first.
+var bulkQueryInProgressWait = 1 * time.Second
+
+// BulkQueryLock serialises the bulk queries posted to a shop. Shopify runs a single bulk query per shop and app at
+// a time, so BulkQuery holds the lock of the shop from before posting the query until the operation is finished.
+// An implementation backed by an external store, e.g. a database or Redis, coordinates several processes.
+type BulkQueryLock interface {
+	// Lock blocks until the caller holds the lock of the shop or the context is done.
+	Lock(ctx context.Context, shop string) error
+	// Unlock releases the lock of the shop held by the caller.
+	Unlock(ctx context.Context, shop string) error
+}
+
+// MemoryBulkQueryLock serialises the bulk queries within the process, granting the lock of a shop in the order
+// it was requested.
+type MemoryBulkQueryLock struct {
+	mu    sync.Mutex
+	shops map[string]*bulkQueryQueue
+}
+
+var _ BulkQueryLock = &MemoryBulkQueryLock{}
+
+// bulkQueryQueue is the lock of a shop, handed over to its waiters in turn.
+type bulkQueryQueue struct {
+	held    bool
+	waiters []chan struct{}
+}
+
+func NewMemoryBulkQueryLock() *MemoryBulkQueryLock {
+	return &MemoryBulkQueryLock{shops: map[string]*bulkQueryQueue{}}
+}
+
+func (l *MemoryBulkQueryLock) Lock(ctx context.Context, shop string) error {
+	l.mu.Lock()
+	q := l.shops[shop]
+	if q == nil {
+		q = &bulkQueryQueue{}
+		l.shops[shop] = q
+	}
+	if !q.held {
+		q.held = true
+		l.mu.Unlock()
+		return nil
+	}
+	granted := make(chan struct{})
+	q.waiters = append(q.waiters, granted)
+	l.mu.Unlock()
+
+	select {
+	case <-granted:
+		return nil
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, w := range q.waiters {
+		if w == granted {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			return ctx.Err()
+		}
+	}
+	// The lock was handed over while the context was done, pass it on.
+	l.handOver(shop, q)
+	return ctx.Err()
+}
+
+func (l *MemoryBulkQueryLock) Unlock(ctx context.Context, shop string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	q := l.shops[shop]
+	if q == nil || !q.held {
+		return fmt.Errorf("bulk query lock of %s isn't held", shop)
+	}
+	l.handOver(shop, q)
+	return nil
+}
+
+// handOver grants the lock to the first waiter, or releases it when there is none.
+func (l *MemoryBulkQueryLock) handOver(shop string, q *bulkQueryQueue) {
+	if len(q.waiters) == 0 {
+		delete(l.shops, shop)
+		return
+	}
+	close(q.waiters[0])
+	q.waiters = q.waiters[1:]
+}
+
+// lockBulkQuery acquires the bulk query lock of the shop and returns the function releasing it, which can be
+// called more than once.
+func (s *BulkOperationServiceOp) lockBulkQuery(ctx context.Context) (func(), error) {
+	lock := s.client.bulkQueryLock
+	if lock == nil {
+		lock = defaultBulkQueryLock
+	}
+	shop := s.client.shopName
+
+	err := lock.Lock(ctx, shop)
+	if err != nil {
+		return nil, fmt.Errorf("lock bulk query: %w", err)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			// The context may be done already, but the lock must be released anyway.
+			err := lock.Unlock(context.Background(), shop)
+			if err != nil {
+				log.Errorf("unlock bulk query: %s", err)
+			}
+		})
+	}, nil
+}
+
+// onBulkOperationFinished calls fn once the operation is no longer running, so the lock can be released while the
+// results are read.
+func onBulkOperationFinished(fn func()) BulkQueryOption {
+	return func(o *bulkQueryOptions) {
+		o.finished = fn
+	}
+}
+
+// isBulkQueryInProgress reports whether the query was rejected because another one is running, e.g. posted by
+// another process.
+func isBulkQueryInProgress(userErrors []model.UserError) bool {
+	for _, e := range userErrors {
+		if strings.Contains(e.Message, "already in progress") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package shopify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vinhluan/go-graphql-client"
+)
+
+func init() {
+	bulkQueryInProgressWait = time.Millisecond
+}
+
+// waitForWaiters blocks until count callers are queued for the lock of the shop.
+func waitForWaiters(t *testing.T, l *MemoryBulkQueryLock, shop string, count int) {
+	require.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.shops[shop] != nil && len(l.shops[shop].waiters) == count
+	}, time.Second, time.Millisecond)
+}
+
+func TestMemoryBulkQueryLockIsFIFO(t *testing.T) {
+	ctx := context.Background()
+	l := NewMemoryBulkQueryLock()
+	require.NoError(t, l.Lock(ctx, "shop"))
+
+	// Another shop isn't serialised with the first one.
+	require.NoError(t, l.Lock(ctx, "other"))
+	require.NoError(t, l.Unlock(ctx, "other"))
+
+	granted := make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		i := i
+		go func() {
+			assert.NoError(t, l.Lock(ctx, "shop"))
+			granted <- i
+		}()
+		waitForWaiters(t, l, "shop", i+1)
+	}
+
+	for want := 0; want < 3; want++ {
+		require.NoError(t, l.Unlock(ctx, "shop"))
+		assert.Equal(t, want, <-granted)
+	}
+	require.NoError(t, l.Unlock(ctx, "shop"))
+	assert.Error(t, l.Unlock(ctx, "shop"))
+	assert.Empty(t, l.shops)
+}
+
+func TestMemoryBulkQueryLockCanceled(t *testing.T) {
+	ctx := context.Background()
+	l := NewMemoryBulkQueryLock()
+	require.NoError(t, l.Lock(ctx, "shop"))
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	canceled := make(chan error, 1)
+	go func() {
+		canceled <- l.Lock(canceledCtx, "shop")
+	}()
+	waitForWaiters(t, l, "shop", 1)
+
+	granted := make(chan error, 1)
+	go func() {
+		granted <- l.Lock(ctx, "shop")
+	}()
+	waitForWaiters(t, l, "shop", 2)
+
+	cancel()
+	assert.ErrorIs(t, <-canceled, context.Canceled)
+	waitForWaiters(t, l, "shop", 1)
+
+	require.NoError(t, l.Unlock(ctx, "shop"))
+	assert.NoError(t, <-granted)
+	require.NoError(t, l.Unlock(ctx, "shop"))
+}
+
+// recordingBulkQueryLock records the calls of the services.
+type recordingBulkQueryLock struct {
+	calls []string
+}
+
+func (l *recordingBulkQueryLock) Lock(ctx context.Context, shop string) error {
+	l.calls = append(l.calls, "lock "+shop)
+	return nil
+}
+
+func (l *recordingBulkQueryLock) Unlock(ctx context.Context, shop string) error {
+	l.calls = append(l.calls, "unlock "+shop)
+	return nil
+}
+
+// inProgressGraphQL rejects the first posted queries as if another process's query was running.
+type inProgressGraphQL struct {
+	*bulkOperationGraphQL
+	rejections int
+}
+
+func (g *inProgressGraphQL) Mutate(ctx context.Context, m interface{}, variables map[string]interface{}) (*graphql.Result, error) {
+	if g.rejections > 0 {
+		g.rejections--
+		m.(*mutationBulkOperationRunQuery).BulkOperationRunQueryResult.UserErrors = []model.UserError{{
+			Message: "A bulk query operation for this app and shop is already in progress: gid://shopify/BulkOperation/1.",
+		}}
+		return &graphql.Result{}, nil
+	}
+	return g.bulkOperationGraphQL.Mutate(ctx, m, variables)
+}
+
+func TestBulkQueryWaitsForOperationsOfOtherProcesses(t *testing.T) {
+	gql := &inProgressGraphQL{
+		bulkOperationGraphQL: &bulkOperationGraphQL{
+			op:       model.BulkOperation{ID: "gid://shopify/BulkOperation/2", Status: model.BulkOperationStatusCompleted, ObjectCount: "0"},
+			postedID: "gid://shopify/BulkOperation/2",
+		},
+		rejections: 2,
+	}
+	lock := &recordingBulkQueryLock{}
+	client := NewClient("shop", WithGraphQLClient(gql), WithBulkQueryLock(lock))
+
+	var res []*model.Product
+	err := client.BulkOperation.BulkQuery(context.Background(), "{ products{ edges{ node{ id } } } }", &res, WithPollInterval(time.Millisecond, time.Millisecond))
+	require.NoError(t, err)
+	assert.Empty(t, res)
+	assert.Zero(t, gql.rejections)
+	assert.Equal(t, 1, gql.posts)
+	assert.Equal(t, []string{"lock shop", "unlock shop"}, lock.calls)
+
+	_, err = client.BulkOperation.PostBulkQuery(context.Background(), "{ products{ edges{ node{ id } } } }")
+	require.NoError(t, err)
+	gql.rejections = 1
+	_, err = client.BulkOperation.PostBulkQuery(context.Background(), "{ products{ edges{ node{ id } } } }")
+	assert.ErrorIs(t, err, ErrBulkQueryInProgress)
+}
+
+func TestBulkQueryWaitForOtherProcessCanceled(t *testing.T) {
+	gql := &bulkOperationGraphQL{
+		op:       model.BulkOperation{ID: "gid://shopify/BulkOperation/1", Status: model.BulkOperationStatusRunning},
+		postedID: "gid://shopify/BulkOperation/2",
+	}
+	client := NewClient("shop", WithGraphQLClient(gql))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var res []*model.Product
+	err := client.BulkOperation.BulkQuery(ctx, "{ products{ edges{ node{ id } } } }", &res)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, gql.posts)
+}
@@ -0,0 +1,304 @@
+package shopify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/goccy/go-json"
+	jsoniter "github.com/json-iterator/go"
+	log "github.com/sirupsen/logrus"
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/sogko/go-shopify-graphql/schema"
+	"gopkg.in/guregu/null.v4"
+)
+
+const bulkMutationVariablesFilename = "bulk_op_vars.jsonl"
+
+type mutationBulkOperationRunMutation struct {
+	BulkOperationRunMutationResult model.BulkOperationRunMutationPayload `graphql:"bulkOperationRunMutation(mutation: $mutation, stagedUploadPath: $stagedUploadPath)" json:"bulkOperationRunMutation"`
+}
+
+// BulkMutationResult is the outcome of the bulk mutation for one of its inputs.
+type BulkMutationResult struct {
+	// Line is the index of the input in the inputs passed to BulkMutation.
+	Line int
+	// UserErrors are the `userErrors` returned by the mutation for the input.
+	UserErrors []model.UserError
+	// Errors are the GraphQL errors returned for the input.
+	Errors []string
+}
+
+type bulkMutationResultLine struct {
+	Data   map[string]jsoniter.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+	LineNumber int `json:"__lineNumber"`
+}
+
+func (s *BulkOperationServiceOp) BulkMutation(ctx context.Context, mutation string, inputs interface{}, out interface{}) ([]BulkMutationResult, error) {
+	// The variables are encoded twice, to measure the upload and then into it, so they're never held in memory.
+	var size byteCounter
+	count, err := writeBulkMutationVariables(&size, mutation, inputs)
+	if err != nil {
+		return nil, fmt.Errorf("write bulk mutation variables: %w", err)
+	}
+
+	outSlice, err := makeBulkMutationOut(out, count)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkMutationResult, count)
+	for i := range results {
+		results[i].Line = i
+	}
+	if count == 0 {
+		return results, nil
+	}
+
+	httpMethod := model.StagedUploadHTTPMethodTypePost
+	target, err := s.client.createStagedUpload(ctx, model.StagedUploadInput{
+		Resource:   model.StagedUploadTargetGenerateUploadResourceBulkMutationVariables,
+		Filename:   bulkMutationVariablesFilename,
+		MimeType:   "text/jsonl",
+		HTTPMethod: &httpMethod,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create staged upload: %w", err)
+	}
+	path, err := stagedUploadPath(target)
+	if err != nil {
+		return nil, err
+	}
+
+	variables, w := io.Pipe()
+	go func() {
+		_, err := writeBulkMutationVariables(w, mutation, inputs)
+		w.CloseWithError(err)
+	}()
+	err = s.client.uploadToStagedTarget(ctx, target, bulkMutationVariablesFilename, variables, int64(size))
+	// Stops the encoding when the upload failed before reading all of it.
+	variables.Close()
+	if err != nil {
+		return nil, fmt.Errorf("upload bulk mutation variables: %w", err)
+	}
+
+	err = s.waitForCurrentBulkMutation(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.PostBulkMutation(ctx, mutation, path)
+	if err != nil {
+		return nil, fmt.Errorf("post bulk mutation: %w", err)
+	}
+
+	q, err := s.waitForBulkOperation(ctx, *id, newBulkQueryOptions(nil))
+	if err != nil {
+		return nil, err
+	}
+	s.owner.release(*id)
+	if q.ID != *id {
+		return nil, fmt.Errorf("Bulk operation %s not found", *id)
+	}
+	if q.Status != model.BulkOperationStatusCompleted {
+		return nil, fmt.Errorf("Bulk operation didn't complete, status=%s, error_code=%s", q.Status, q.ErrorCode)
+	}
+	if q.URL == nil || *q.URL == "" {
+		return results, nil
+	}
+
+	_, err = s.client.streamBulkResult(ctx, *q.URL, parseBulkCount(q.FileSize), func(r io.Reader) (int, error) {
+		err := parseBulkMutationResult(r, results, outSlice)
+		if err != nil {
+			return 0, fmt.Errorf("parse bulk mutation result: %w", err)
+		}
+		return len(results), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (s *BulkOperationServiceOp) PostBulkMutation(ctx context.Context, mutation string, stagedUploadPath string) (*string, error) {
+	m := mutationBulkOperationRunMutation{}
+	vars := map[string]interface{}{
+		"mutation":         null.StringFrom(mutation),
+		"stagedUploadPath": null.StringFrom(stagedUploadPath),
+	}
+
+	err := s.client.Mutate(ctx, &m, vars)
+	if err != nil {
+		return nil, fmt.Errorf("error posting bulk mutation: %w", err)
+	}
+	if len(m.BulkOperationRunMutationResult.UserErrors) > 0 {
+		errors, _ := json.MarshalIndent(m.BulkOperationRunMutationResult.UserErrors, "", "    ")
+		return nil, fmt.Errorf("error posting bulk mutation: %s", errors)
+	}
+	if m.BulkOperationRunMutationResult.BulkOperation == nil {
+		return nil, fmt.Errorf("Posted operation is nil")
+	}
+	s.owner.own(m.BulkOperationRunMutationResult.BulkOperation.ID)
+
+	return &m.BulkOperationRunMutationResult.BulkOperation.ID, nil
+}
+
+func (s *BulkOperationServiceOp) getCurrentBulkMutation(ctx context.Context) (*model.BulkOperation, error) {
+	var q struct {
+		CurrentBulkOperation struct {
+			model.BulkOperation
+		} `graphql:"currentBulkOperation(type: $type)"`
+	}
+	vars := map[string]interface{}{
+		"type": model.BulkOperationTypeMutation,
+	}
+	err := s.client.Query(ctx, &q, vars)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	return &q.CurrentBulkOperation.BulkOperation, nil
+}
+
+// waitForCurrentBulkMutation waits for the bulk mutation running in the shop, if any, to finish.
+func (s *BulkOperationServiceOp) waitForCurrentBulkMutation(ctx context.Context) error {
+	q, err := s.getCurrentBulkMutation(ctx)
+	if err != nil {
+		return fmt.Errorf("CurrentBulkOperation query error: %w", err)
+	}
+	if q.ID == "" || !isBulkOperationRunning(q) {
+		return nil
+	}
+
+	log.Debugf("Waiting for the current bulk mutation %s to finish", q.ID)
+	_, err = s.waitForBulkOperation(ctx, q.ID, newBulkQueryOptions(nil))
+	return err
+}
+
+// writeBulkMutationVariables writes a JSONL line of variables for every item of the inputs slice. When the mutation
+// declares a single variable, the items are its values, otherwise each item must encode all the variables of a line.
+func writeBulkMutationVariables(w io.Writer, mutation string, inputs interface{}) (int, error) {
+	doc, err := schema.ParseQuery(mutation)
+	if err != nil {
+		return 0, fmt.Errorf("parse mutation: %w", err)
+	}
+	if len(doc.Operations) != 1 || doc.Operations[0].Type != schema.OperationMutation {
+		return 0, fmt.Errorf("the mutation arg must hold a single mutation operation")
+	}
+	var variable string
+	if defs := doc.Operations[0].VariableDefinitions; len(defs) == 1 {
+		variable = defs[0].Name
+	}
+
+	inputsValue := reflect.ValueOf(inputs)
+	if inputsValue.Kind() != reflect.Slice {
+		return 0, fmt.Errorf("the inputs arg is not a slice")
+	}
+
+	enc := json.NewEncoder(w)
+	for i := 0; i < inputsValue.Len(); i++ {
+		var line interface{} = inputsValue.Index(i).Interface()
+		if variable != "" {
+			line = map[string]interface{}{variable: line}
+		}
+		err = enc.Encode(line)
+		if err != nil {
+			return 0, fmt.Errorf("encode input %d: %w", i, err)
+		}
+	}
+
+	return inputsValue.Len(), nil
+}
+
+// byteCounter counts the bytes written into it.
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+// makeBulkMutationOut sets out, a nil or a pointer to a slice of the mutation payload type, to a slice of n items.
+func makeBulkMutationOut(out interface{}, n int) (reflect.Value, error) {
+	if out == nil {
+		return reflect.Value{}, nil
+	}
+
+	outValue := reflect.ValueOf(out)
+	if outValue.Kind() != reflect.Ptr || outValue.Elem().Kind() != reflect.Slice {
+		return reflect.Value{}, fmt.Errorf("the out arg is not a pointer to a slice")
+	}
+
+	outSlice := outValue.Elem()
+	outSlice.Set(reflect.MakeSlice(outSlice.Type(), n, n))
+
+	return outSlice, nil
+}
+
+// parseBulkMutationResult maps every line of the result back to the result and the out item of its input.
+func parseBulkMutationResult(r io.Reader, results []BulkMutationResult, outSlice reflect.Value) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			perr := parseBulkMutationResultLine(line, results, outSlice)
+			if perr != nil {
+				return perr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading the result file: %w", err)
+		}
+	}
+}
+
+func parseBulkMutationResultLine(line []byte, results []BulkMutationResult, outSlice reflect.Value) error {
+	var l bulkMutationResultLine
+	err := jsoniter.ConfigFastest.Unmarshal(line, &l)
+	if err != nil {
+		return fmt.Errorf("unmarshalling: %w", err)
+	}
+	if l.LineNumber < 0 || l.LineNumber >= len(results) {
+		return fmt.Errorf("result line number %d is out of range of %d inputs", l.LineNumber, len(results))
+	}
+
+	result := &results[l.LineNumber]
+	for _, e := range l.Errors {
+		result.Errors = append(result.Errors, e.Message)
+	}
+
+	// The data holds a single field named after the mutation, e.g. `productCreate`.
+	for _, payload := range l.Data {
+		if len(payload) == 0 || string(payload) == "null" {
+			continue
+		}
+
+		var userErrors struct {
+			UserErrors []model.UserError `json:"userErrors"`
+		}
+		err = jsoniter.ConfigFastest.Unmarshal(payload, &userErrors)
+		if err != nil {
+			return fmt.Errorf("unmarshalling user errors: %w", err)
+		}
+		result.UserErrors = append(result.UserErrors, userErrors.UserErrors...)
+
+		if outSlice.IsValid() {
+			err = jsoniter.ConfigFastest.Unmarshal(payload, outSlice.Index(l.LineNumber).Addr().Interface())
+			if err != nil {
+				return fmt.Errorf("unmarshalling: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package shopify
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBulkMutationVariables(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := writeBulkMutationVariables(&buf, `mutation($input: ProductInput!){ productCreate(input: $input){ userErrors{ message } } }`, []model.ProductInput{
+		{Title: strPtr("Shirt")},
+		{Title: strPtr("Hat")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "{\"input\":{\"title\":\"Shirt\"}}\n{\"input\":{\"title\":\"Hat\"}}\n", buf.String())
+
+	buf.Reset()
+	_, err = writeBulkMutationVariables(&buf, `mutation($id: ID!, $input: ProductInput!){ productUpdate(input: $input){ userErrors{ message } } }`, []map[string]interface{}{
+		{"id": "gid://shopify/Product/1", "input": map[string]string{"title": "Shirt"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "{\"id\":\"gid://shopify/Product/1\",\"input\":{\"title\":\"Shirt\"}}\n", buf.String())
+
+	_, err = writeBulkMutationVariables(&buf, `{ shop{ name } }`, []model.ProductInput{})
+	assert.EqualError(t, err, "the mutation arg must hold a single mutation operation")
+}
+
+func TestParseBulkMutationResult(t *testing.T) {
+	fixture := `{"data":{"productCreate":{"product":{"id":"gid://shopify/Product/2","title":"Hat"},"userErrors":[]}},"__lineNumber":1}
+{"data":{"productCreate":{"product":null,"userErrors":[{"field":["input","title"],"message":"Title can't be blank"}]}},"__lineNumber":0}
+{"errors":[{"message":"Internal error"}],"__lineNumber":2}
+`
+
+	results := make([]BulkMutationResult, 3)
+	var out []model.ProductCreatePayload
+	outSlice, err := makeBulkMutationOut(&out, len(results))
+	require.NoError(t, err)
+
+	require.NoError(t, parseBulkMutationResult(strings.NewReader(fixture), results, outSlice))
+	require.Len(t, out, 3)
+	assert.Nil(t, out[0].Product)
+	assert.Equal(t, "Hat", out[1].Product.Title)
+	assert.Equal(t, []model.UserError{{Field: []string{"input", "title"}, Message: "Title can't be blank"}}, results[0].UserErrors)
+	assert.Empty(t, results[1].UserErrors)
+	assert.Equal(t, []string{"Internal error"}, results[2].Errors)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,244 @@
+package shopify
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/sogko/go-shopify-graphql/model"
+)
+
+// bulkTagName is the struct tag listing the GraphQL types of the nodes of a connection field, e.g.
+// `bulk:"MediaImage,Video"`, for connections whose Node type isn't named after the GraphQL type.
+const bulkTagName = "bulk"
+
+var (
+	bulkNodeTypesMu sync.RWMutex
+	// bulkNodeTypes maps GraphQL types to the Go types their nested objects are decoded into, for the connections
+	// whose Node is an interface or a type named differently.
+	bulkNodeTypes = map[string]reflect.Type{
+		"MediaImage":    reflect.TypeOf(model.MediaImage{}),
+		"Video":         reflect.TypeOf(model.Video{}),
+		"Model3d":       reflect.TypeOf(model.Model3d{}),
+		"ExternalVideo": reflect.TypeOf(model.ExternalVideo{}),
+		"ProductImage":  reflect.TypeOf(model.Image{}),
+	}
+)
+
+// RegisterBulkNodeType registers node, a struct or a pointer to a struct, as the type the nested objects of the
+// GraphQL type typename are decoded into by BulkQuery. It's only needed for connections whose Node is an interface
+// or a type not named after typename.
+func RegisterBulkNodeType(typename string, node interface{}) {
+	t := reflect.TypeOf(node)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("bulk node type of %s must be a struct, got %T", typename, node))
+	}
+
+	bulkNodeTypesMu.Lock()
+	defer bulkNodeTypesMu.Unlock()
+	bulkNodeTypes[typename] = t
+}
+
+func registeredBulkNodeType(typename string) reflect.Type {
+	bulkNodeTypesMu.RLock()
+	defer bulkNodeTypesMu.RUnlock()
+	return bulkNodeTypes[typename]
+}
+
+type bulkConnectionKey struct {
+	parentType reflect.Type
+	typename   string
+}
+
+// bulkConnection is the connection field of a parent type that holds the nested objects of a GraphQL type, with
+// the indexes of the fields leading to the nodes resolved once, so attaching a node takes no field lookup by name.
+type bulkConnection struct {
+	index      []int
+	edgesIndex []int
+	nodeIndex  []int
+	nodeType   reflect.Type
+}
+
+// bulkNodeTypename returns the GraphQL type of a nested object from its `__typename` field or else from its gid.
+func bulkNodeTypename(typename string, gid string) (string, error) {
+	if typename != "" {
+		return typename, nil
+	}
+	typename, ok := gidTypename(gid)
+	if !ok {
+		return "", fmt.Errorf("malformed gid=`%s`", gid)
+	}
+	return typename, nil
+}
+
+// gidTypename returns the type of a global ID, e.g. `Product` for `gid://shopify/Product/1`.
+func gidTypename(gid string) (string, bool) {
+	const prefix = "gid://shopify/"
+	if !strings.HasPrefix(gid, prefix) {
+		return "", false
+	}
+	rest := gid[len(prefix):]
+	end := strings.IndexByte(rest, '/')
+	if end <= 0 || end == len(rest)-1 {
+		return "", false
+	}
+	return rest[:end], true
+}
+
+// findBulkConnection looks for the connection field of parentType, including the fields of embedded structs,
+// whose nodes can hold an object of the GraphQL type typename. When several can, the one tagged with typename
+// wins, then the one named after the plural of typename, e.g. LineItems over NonFulfillableLineItems.
+func findBulkConnection(parentType reflect.Type, typename string) (*bulkConnection, error) {
+	var (
+		found  []*bulkConnection
+		fields []string
+		tagged []bool
+	)
+	err := visitConnectionFields(parentType, nil, func(field reflect.StructField, index []int, edgeType reflect.Type) error {
+		nodeType, err := connectionNodeType(field, edgeType, typename)
+		if err != nil || nodeType == nil {
+			return err
+		}
+
+		connType := field.Type
+		if connType.Kind() == reflect.Ptr {
+			connType = connType.Elem()
+		}
+		edges, _ := connType.FieldByName(edgesFieldName)
+		node, _ := edgeType.FieldByName(nodeFieldName)
+
+		_, hasTag := field.Tag.Lookup(bulkTagName)
+		fields = append(fields, field.Name)
+		tagged = append(tagged, hasTag)
+		found = append(found, &bulkConnection{index: index, edgesIndex: edges.Index, nodeIndex: node.Index, nodeType: nodeType})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no connection of %s nodes is defined on the parent type %s", typename, parentType.String())
+	case 1:
+		return found[0], nil
+	}
+
+	if i, ok := singleMatch(len(found), func(i int) bool { return tagged[i] }); ok {
+		return found[i], nil
+	}
+	if i, ok := singleMatch(len(found), func(i int) bool { return fields[i] == typename+"s" }); ok {
+		return found[i], nil
+	}
+	return nil, fmt.Errorf("connections %s of the parent type %s all hold %s nodes, tag the one queried with `%s:\"%s\"`",
+		strings.Join(fields, ", "), parentType.String(), typename, bulkTagName, typename)
+}
+
+// singleMatch returns the index of the only one of the n elements matching match.
+func singleMatch(n int, match func(i int) bool) (int, bool) {
+	index := -1
+	for i := 0; i < n; i++ {
+		if !match(i) {
+			continue
+		}
+		if index >= 0 {
+			return 0, false
+		}
+		index = i
+	}
+	return index, index >= 0
+}
+
+// visitConnectionFields calls fn with every field of the struct t whose type is a connection, i.e. a struct or
+// a pointer to a struct with an Edges slice of structs with a Node field.
+func visitConnectionFields(t reflect.Type, index []int, fn func(field reflect.StructField, index []int, edgeType reflect.Type) error) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			err := visitConnectionFields(field.Type, fieldIndex, fn)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		connType := field.Type
+		if connType.Kind() == reflect.Ptr {
+			connType = connType.Elem()
+		}
+		if connType.Kind() != reflect.Struct {
+			continue
+		}
+		edges, ok := connType.FieldByName(edgesFieldName)
+		if !ok || edges.Type.Kind() != reflect.Slice || edges.Type.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		edgeType := edges.Type.Elem()
+		if _, ok := edgeType.FieldByName(nodeFieldName); !ok {
+			continue
+		}
+
+		err := fn(field, fieldIndex, edgeType)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connectionNodeType returns the struct type to decode an object of the GraphQL type typename into, when the
+// connection field can hold it, or nil otherwise.
+func connectionNodeType(field reflect.StructField, edgeType reflect.Type, typename string) (reflect.Type, error) {
+	node, _ := edgeType.FieldByName(nodeFieldName)
+
+	var nodeType reflect.Type
+	switch node.Type.Kind() {
+	case reflect.Ptr:
+		nodeType = node.Type.Elem()
+	case reflect.Interface:
+	default:
+		return nil, fmt.Errorf("Node of the connection '%s' must be a pointer or an interface, got %s", field.Name, node.Type.String())
+	}
+	registered := registeredBulkNodeType(typename)
+
+	if tag, ok := field.Tag.Lookup(bulkTagName); ok {
+		if !containsString(strings.Split(tag, ","), typename) {
+			return nil, nil
+		}
+		if nodeType != nil {
+			return nodeType, nil
+		}
+		if registered == nil {
+			return nil, fmt.Errorf("Node of the connection '%s' is an interface, register the type of %s nodes with RegisterBulkNodeType", field.Name, typename)
+		}
+	}
+
+	if nodeType != nil {
+		if nodeType.Name() == typename || nodeType == registered {
+			return nodeType, nil
+		}
+		return nil, nil
+	}
+	if registered != nil && reflect.PtrTo(registered).AssignableTo(node.Type) {
+		return registered, nil
+	}
+	return nil, nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) == s {
+			return true
+		}
+	}
+	return false
+}
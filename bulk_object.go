@@ -0,0 +1,157 @@
+package shopify
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// bulkObjectJSON decodes the numbers as json.Number, so they're written back as they were read.
+var bulkObjectJSON = jsoniter.Config{UseNumber: true}.Froze()
+
+// BulkObject is an object of the JSONL result of a bulk query, linked to its parent and to the objects nested in it,
+// for queries without a Go type to decode their results into. BulkQuery decodes the results into a *[]*BulkObject,
+// or into a *[]map[string]interface{} holding the maps of the root objects, see Map.
+type BulkObject struct {
+	ID string
+	// Typename is the `__typename` of the object, or else the type of its ID.
+	Typename string
+	// Fields holds the fields of the object as decoded from JSON, with the numbers as json.Number, and the
+	// `__parentId` of a nested object.
+	Fields   map[string]interface{}
+	Parent   *BulkObject
+	Children []*BulkObject
+}
+
+// Map returns the fields of the object, with the maps of the objects nested in it listed under their typename,
+// e.g. `ProductVariant` for the variants of a product. Typenames don't collide with the fields, whose names start
+// with a lower case letter.
+func (obj *BulkObject) Map() map[string]interface{} {
+	m := make(map[string]interface{}, len(obj.Fields)+1)
+	for k, v := range obj.Fields {
+		m[k] = v
+	}
+	for _, child := range obj.Children {
+		children, _ := m[child.Typename].([]interface{})
+		m[child.Typename] = append(children, child.Map())
+	}
+	return m
+}
+
+// bulkObjectReader reads the JSONL result of a bulk query one root object at a time, with the objects nested in it,
+// without decoding them into Go types.
+type bulkObjectReader struct {
+	lines *bulkLineReader
+	json  jsoniter.API
+	// nodes holds the objects of the current root by ID.
+	nodes map[string]*BulkObject
+}
+
+func newBulkObjectReader(r io.Reader) *bulkObjectReader {
+	return &bulkObjectReader{
+		lines: newBulkLineReader(r),
+		json:  bulkObjectJSON,
+		nodes: make(map[string]*BulkObject),
+	}
+}
+
+// Read returns the next root object with the objects nested in it, or io.EOF when there are no more objects.
+func (o *bulkObjectReader) Read() (*BulkObject, error) {
+	var root *BulkObject
+	for {
+		line, err := o.lines.read()
+		if errors.Is(err, io.EOF) {
+			if root != nil {
+				return root, nil
+			}
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading the result file: %w", err)
+		}
+
+		fields := map[string]interface{}{}
+		err = o.json.Unmarshal(line, &fields)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshalling: %w", err)
+		}
+		obj := &BulkObject{Fields: fields}
+		obj.ID, _ = fields["id"].(string)
+		obj.Typename, _ = fields["__typename"].(string)
+		if obj.Typename == "" {
+			obj.Typename, _ = gidTypename(obj.ID)
+		}
+
+		if parentID, ok := fields["__parentId"].(string); ok {
+			if root == nil {
+				return nil, fmt.Errorf("object with __parentId `%s` precedes its parent", parentID)
+			}
+			parent, ok := o.nodes[parentID]
+			if !ok {
+				return nil, fmt.Errorf("parent `%s` not found, the parent objects must query the `id` field", parentID)
+			}
+			if obj.Typename == "" {
+				return nil, fmt.Errorf("The connection type must query the `id` or `__typename` field")
+			}
+			obj.Parent = parent
+			parent.Children = append(parent.Children, obj)
+			if obj.ID != "" {
+				o.nodes[obj.ID] = obj
+			}
+			continue
+		}
+
+		if root != nil {
+			o.lines.unread(line)
+			return root, nil
+		}
+
+		root = obj
+		for id := range o.nodes {
+			delete(o.nodes, id)
+		}
+		if obj.ID != "" {
+			o.nodes[obj.ID] = obj
+		}
+	}
+}
+
+// descendants returns the objects nested in obj along the chain of typenames.
+func (obj *BulkObject) descendants(chain []string) []*BulkObject {
+	objects := []*BulkObject{obj}
+	for _, typename := range chain {
+		var children []*BulkObject
+		for _, o := range objects {
+			for _, child := range o.Children {
+				if child.Typename == typename {
+					children = append(children, child)
+				}
+			}
+		}
+		objects = children
+	}
+	return objects
+}
+
+// value returns the value at the path of the fields of the object, or nil when there's none.
+func (obj *BulkObject) value(path []string) interface{} {
+	var v interface{} = obj.Fields
+	for _, key := range path {
+		switch container := v.(type) {
+		case map[string]interface{}:
+			v = container[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(container) {
+				return nil
+			}
+			v = container[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
@@ -0,0 +1,92 @@
+package shopify
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bulkObjectFixture = `{"id":"gid://shopify/Product/1","title":"Shirt"}
+{"id":"gid://shopify/ProductVariant/11","sku":"SHIRT-S","inventoryQuantity":3,"__parentId":"gid://shopify/Product/1"}
+{"__typename":"MediaImage","id":"gid://shopify/MediaImage/111","__parentId":"gid://shopify/ProductVariant/11"}
+{"id":"gid://shopify/ProductVariant/12","sku":"SHIRT-M","inventoryQuantity":0,"__parentId":"gid://shopify/Product/1"}
+{"id":"gid://shopify/Product/2","title":"Hat"}
+`
+
+func TestParseBulkQueryResultIntoBulkObjects(t *testing.T) {
+	var res []*BulkObject
+	n, err := parseBulkQueryResult(strings.NewReader(bulkObjectFixture), &res)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	require.Len(t, res, 2)
+
+	shirt := res[0]
+	assert.Equal(t, "gid://shopify/Product/1", shirt.ID)
+	assert.Equal(t, "Product", shirt.Typename)
+	assert.Nil(t, shirt.Parent)
+	require.Len(t, shirt.Children, 2)
+
+	variant := shirt.Children[0]
+	assert.Equal(t, "ProductVariant", variant.Typename)
+	assert.Same(t, shirt, variant.Parent)
+	assert.Equal(t, "SHIRT-S", variant.Fields["sku"])
+	assert.Equal(t, json.Number("3"), variant.Fields["inventoryQuantity"])
+	require.Len(t, variant.Children, 1)
+	assert.Equal(t, "MediaImage", variant.Children[0].Typename)
+	assert.Same(t, variant, variant.Children[0].Parent)
+
+	assert.Empty(t, res[1].Children)
+}
+
+func TestParseBulkQueryResultIntoMaps(t *testing.T) {
+	var res []map[string]interface{}
+	n, err := parseBulkQueryResult(strings.NewReader(bulkObjectFixture), &res)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []map[string]interface{}{{
+		"id":    "gid://shopify/Product/1",
+		"title": "Shirt",
+		"ProductVariant": []interface{}{
+			map[string]interface{}{
+				"id":                "gid://shopify/ProductVariant/11",
+				"sku":               "SHIRT-S",
+				"inventoryQuantity": json.Number("3"),
+				"__parentId":        "gid://shopify/Product/1",
+				"MediaImage": []interface{}{
+					map[string]interface{}{
+						"__typename": "MediaImage",
+						"id":         "gid://shopify/MediaImage/111",
+						"__parentId": "gid://shopify/ProductVariant/11",
+					},
+				},
+			},
+			map[string]interface{}{
+				"id":                "gid://shopify/ProductVariant/12",
+				"sku":               "SHIRT-M",
+				"inventoryQuantity": json.Number("0"),
+				"__parentId":        "gid://shopify/Product/1",
+			},
+		},
+	}, {
+		"id":    "gid://shopify/Product/2",
+		"title": "Hat",
+	}}, res)
+}
+
+func TestStreamBulkQueryResultAsMaps(t *testing.T) {
+	var titles []interface{}
+	n, err := streamBulkQueryResult(strings.NewReader(bulkObjectFixture), func(m map[string]interface{}) error {
+		titles = append(titles, m["title"])
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []interface{}{"Shirt", "Hat"}, titles)
+
+	_, err = streamBulkQueryResult(strings.NewReader(`{"id":"gid://shopify/Product/1"}
+{"title":"untyped","__parentId":"gid://shopify/Product/1"}`), func(obj *BulkObject) error { return nil })
+	assert.EqualError(t, err, "The connection type must query the `id` or `__typename` field")
+}
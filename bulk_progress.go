@@ -0,0 +1,150 @@
+package shopify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sogko/go-shopify-graphql/model"
+)
+
+const (
+	defaultMinPollInterval = 1 * time.Second
+	defaultMaxPollInterval = 30 * time.Second
+	// pollBackoff is the factor the poll interval grows by on every poll, up to the max interval.
+	pollBackoff = 1.5
+)
+
+// BulkProgress reports the state of a bulk operation on every poll while waiting for it.
+type BulkProgress struct {
+	ID     string
+	Status model.BulkOperationStatus
+	// StatusChanged is set on the first poll and whenever the status differs from the previous poll.
+	StatusChanged bool
+	// ObjectCount is the count of objects written so far, including the nested ones.
+	ObjectCount int64
+	// RootObjectCount is the count of root objects written so far.
+	RootObjectCount int64
+	// FileSize is the size of the result file in bytes, once the operation completed.
+	FileSize int64
+	// Elapsed is the time since the operation was created.
+	Elapsed time.Duration
+	// ETA estimates the time left from the rate of root objects, when their expected count is set with
+	// WithExpectedRootObjects. It's zero when unknown.
+	ETA time.Duration
+}
+
+// WithProgress calls fn with the progress of the bulk operation on every poll.
+func WithProgress(fn func(BulkProgress)) BulkQueryOption {
+	return func(o *bulkQueryOptions) {
+		o.progress = fn
+	}
+}
+
+// WithPollInterval sets the interval between the polls of the bulk operation. It starts at minInterval and grows
+// as the operation runs longer, up to maxInterval.
+func WithPollInterval(minInterval, maxInterval time.Duration) BulkQueryOption {
+	return func(o *bulkQueryOptions) {
+		o.minPollInterval = minInterval
+		o.maxPollInterval = maxInterval
+		o.pollIntervalSet = true
+	}
+}
+
+// WithExpectedRootObjects sets the count of root objects the bulk query is expected to return, e.g. from
+// a `productsCount` query, to estimate the BulkProgress ETA.
+func WithExpectedRootObjects(n int64) BulkQueryOption {
+	return func(o *bulkQueryOptions) {
+		o.expectedRootObjects = n
+	}
+}
+
+// waitForBulkOperation polls the bulk operation until it's finished, reporting its progress on every poll. With the
+// BULK_OPERATIONS_FINISH webhook subscribed, it also polls on the delivery for the operation, and only every fallback
+// interval unless the caller set the poll interval.
+func (s *BulkOperationServiceOp) waitForBulkOperation(ctx context.Context, id string, options *bulkQueryOptions) (*model.BulkOperation, error) {
+	finished, stop := s.finish.wait(id)
+	defer stop()
+
+	started := time.Now()
+	interval := options.minPollInterval
+	adaptive := finished == nil || options.pollIntervalSet
+	if !adaptive {
+		interval = options.webhookFallbackInterval
+	}
+	var previous model.BulkOperationStatus
+	for {
+		q, err := s.GetBulkOperation(ctx, id)
+		if err != nil {
+			return q, fmt.Errorf("BulkOperation query error: %w", err)
+		}
+
+		if options.progress != nil {
+			options.progress(newBulkProgress(q, previous, started, options.expectedRootObjects))
+		}
+		previous = q.Status
+
+		if !isBulkOperationRunning(q) {
+			log.Debugf("Bulk operation %s ready, latest status=%s", id, q.Status)
+			return q, nil
+		}
+		log.Debugf("Bulk operation %s is still %s...", id, q.Status)
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return q, ctx.Err()
+		case <-finished:
+			timer.Stop()
+		case <-timer.C:
+		}
+		if adaptive {
+			interval = nextPollInterval(interval, options.maxPollInterval)
+		}
+	}
+}
+
+func nextPollInterval(interval, maxInterval time.Duration) time.Duration {
+	next := time.Duration(float64(interval) * pollBackoff)
+	if next > maxInterval {
+		return maxInterval
+	}
+	return next
+}
+
+// newBulkProgress reports the operation, measuring the elapsed time from its creation or else from started.
+func newBulkProgress(q *model.BulkOperation, previous model.BulkOperationStatus, started time.Time, expectedRootObjects int64) BulkProgress {
+	p := BulkProgress{
+		ID:              q.ID,
+		Status:          q.Status,
+		StatusChanged:   q.Status != previous,
+		ObjectCount:     parseBulkCount(&q.ObjectCount),
+		RootObjectCount: parseBulkCount(&q.RootObjectCount),
+		FileSize:        parseBulkCount(q.FileSize),
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, q.CreatedAt)
+	if err != nil || createdAt.After(time.Now()) {
+		createdAt = started
+	}
+	p.Elapsed = time.Since(createdAt)
+
+	if expectedRootObjects > 0 && p.RootObjectCount > 0 && p.RootObjectCount < expectedRootObjects {
+		perObject := p.Elapsed / time.Duration(p.RootObjectCount)
+		p.ETA = perObject * time.Duration(expectedRootObjects-p.RootObjectCount)
+	}
+
+	return p
+}
+
+// parseBulkCount parses the counts of a bulk operation, which the API returns as strings.
+func parseBulkCount(s *string) int64 {
+	if s == nil {
+		return 0
+	}
+	n, _ := strconv.ParseInt(*s, 10, 64)
+	return n
+}
@@ -0,0 +1,103 @@
+package shopify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextPollInterval(t *testing.T) {
+	assert.Equal(t, 1500*time.Millisecond, nextPollInterval(time.Second, 30*time.Second))
+	assert.Equal(t, 30*time.Second, nextPollInterval(25*time.Second, 30*time.Second))
+}
+
+func TestNewBulkProgress(t *testing.T) {
+	fileSize := "2048"
+	createdAt := time.Now().Add(-10 * time.Minute)
+	q := &model.BulkOperation{
+		ID:              "gid://shopify/BulkOperation/1",
+		Status:          model.BulkOperationStatusRunning,
+		CreatedAt:       createdAt.Format(time.RFC3339),
+		ObjectCount:     "5000",
+		RootObjectCount: "250",
+		FileSize:        &fileSize,
+	}
+
+	p := newBulkProgress(q, model.BulkOperationStatusCreated, time.Now(), 1000)
+	assert.True(t, p.StatusChanged)
+	assert.Equal(t, int64(5000), p.ObjectCount)
+	assert.Equal(t, int64(250), p.RootObjectCount)
+	assert.Equal(t, int64(2048), p.FileSize)
+	assert.InDelta(t, (10 * time.Minute).Seconds(), p.Elapsed.Seconds(), 2)
+	assert.InDelta(t, (30 * time.Minute).Seconds(), p.ETA.Seconds(), 10)
+
+	p = newBulkProgress(q, model.BulkOperationStatusRunning, time.Now(), 0)
+	assert.False(t, p.StatusChanged)
+	assert.Zero(t, p.ETA)
+}
+
+func TestWaitForBulkOperationReportsProgress(t *testing.T) {
+	id := "gid://shopify/BulkOperation/1"
+	gql := &bulkOperationGraphQL{upcoming: []model.BulkOperation{
+		{ID: id, Status: model.BulkOperationStatusCreated, ObjectCount: "0"},
+		{ID: id, Status: model.BulkOperationStatusRunning, ObjectCount: "10"},
+		{ID: id, Status: model.BulkOperationStatusRunning, ObjectCount: "20"},
+		{ID: id, Status: model.BulkOperationStatusCompleted, ObjectCount: "30"},
+	}}
+	client := NewClient("test", WithGraphQLClient(gql))
+
+	var progress []BulkProgress
+	q, err := client.BulkOperation.WaitForBulkOperation(context.Background(), id, time.Millisecond, WithProgress(func(p BulkProgress) {
+		progress = append(progress, p)
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, model.BulkOperationStatusCompleted, q.Status)
+
+	require.Len(t, progress, 4)
+	var changed []bool
+	var counts []int64
+	for _, p := range progress {
+		changed = append(changed, p.StatusChanged)
+		counts = append(counts, p.ObjectCount)
+	}
+	assert.Equal(t, []bool{true, true, false, true}, changed)
+	assert.Equal(t, []int64{0, 10, 20, 30}, counts)
+}
+
+func TestWaitForBulkOperationCanceledContext(t *testing.T) {
+	gql := &bulkOperationGraphQL{op: model.BulkOperation{ID: "gid://shopify/BulkOperation/1", Status: model.BulkOperationStatusRunning}}
+	client := NewClient("test", WithGraphQLClient(gql))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.BulkOperation.WaitForBulkOperation(ctx, gql.op.ID, time.Hour)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWaitForCurrentBulkQuery(t *testing.T) {
+	id := "gid://shopify/BulkOperation/1"
+	gql := &bulkOperationGraphQL{upcoming: []model.BulkOperation{
+		{ID: id, Status: model.BulkOperationStatusRunning, ObjectCount: "0"},
+		{ID: id, Status: model.BulkOperationStatusRunning, ObjectCount: "10"},
+		{ID: id, Status: model.BulkOperationStatusCompleted, ObjectCount: "20"},
+	}}
+	client := NewClient("test", WithGraphQLClient(gql))
+
+	var counts []int64
+	q, err := client.BulkOperation.WaitForCurrentBulkQuery(context.Background(), time.Millisecond, WithProgress(func(p BulkProgress) {
+		counts = append(counts, p.ObjectCount)
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, model.BulkOperationStatusCompleted, q.Status)
+	assert.Equal(t, []int64{10, 20}, counts)
+
+	gql.op = model.BulkOperation{ID: id, Status: model.BulkOperationStatusRunning}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.BulkOperation.WaitForCurrentBulkQuery(ctx, time.Hour)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,542 @@
+package shopify
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/sogko/go-shopify-graphql/schema"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	modelPkgPath        = reflect.TypeOf(model.Product{}).PkgPath()
+)
+
+// BulkQueryBuildOption optionally changes the document built by BuildBulkQuery.
+type BulkQueryBuildOption func(*bulkQueryBuild)
+
+type bulkQueryBuild struct {
+	filter string
+	fields []string
+}
+
+// WithBulkFilter sets the `query` argument of the root connection, e.g. `status:active`.
+func WithBulkFilter(query string) BulkQueryBuildOption {
+	return func(b *bulkQueryBuild) {
+		b.filter = query
+	}
+}
+
+// WithBulkFields selects the fields of the query by their dot separated paths, e.g. `variants.sku`, instead of
+// every field of the Go type. Selecting a field selects its parents, and every field of it unless some of its
+// own fields are selected too.
+func WithBulkFields(paths ...string) BulkQueryBuildOption {
+	return func(b *bulkQueryBuild) {
+		b.fields = append(b.fields, paths...)
+	}
+}
+
+// BuildBulkQuery builds the document of a bulk query on the root connection, e.g. `products`, from the Go type
+// its results are decoded into: out is a slice such as `[]*model.Product` or a func passed to BulkQueryEach.
+//
+// The GraphQL name of a field is its `json` tag name, or else its Go name starting with a lower case letter.
+// A `graphql` tag sets the field as written in the query, e.g. `graphql:"metafield(key: \"color\")"`, and fields
+// tagged `json:"-"` are skipped. Connections are queried without `first` and the `id` of their nodes is always
+// selected, as bulk results link nested objects to their parent by ID.
+//
+// Selecting every field of a struct leaves out the fields that can't be queried, like the cyclic ones, the
+// interfaces or the connections within lists, and selects only the `id` of the other resources it refers to. For
+// the models, it also leaves out their connections, as a bulk query holds a few only, and the fields with required
+// arguments when the schema of the API version has them.
+func BuildBulkQuery(connection string, out interface{}, opts ...BulkQueryBuildOption) (string, error) {
+	b := &bulkQueryBuild{}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	itemType, err := bulkItemType(out)
+	if err != nil {
+		return "", err
+	}
+
+	selection := newFieldSelection(b.fields)
+	w := &bulkQueryWriter{visiting: map[reflect.Type]bool{}}
+	if s, err := schema.Load(defaultShopifyAPIVersion); err == nil {
+		w.schema = s
+	}
+	w.connections = 1
+
+	var sb strings.Builder
+	sb.WriteString("{ ")
+	sb.WriteString(connection)
+	sb.WriteString(searchArgument(b.filter))
+	sb.WriteString(" { edges { node ")
+	err = w.writeNode(&sb, itemType, connection, selection, 0)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(" } } }")
+
+	if w.connections > schema.MaxBulkConnections {
+		return "", fmt.Errorf("bulk queries can hold at most %d connections, got %d", schema.MaxBulkConnections, w.connections)
+	}
+	return sb.String(), nil
+}
+
+// bulkItemType returns the type of the root objects of out, a slice, a pointer to a slice or a func taking them.
+func bulkItemType(out interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(out)
+	if t == nil {
+		return nil, fmt.Errorf("the out arg is nil")
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.Slice:
+		t = t.Elem()
+	case t.Kind() == reflect.Func && t.NumIn() == 1:
+		t = t.In(0)
+	default:
+		return nil, fmt.Errorf("the out arg is not a slice or a func(T) error, got %s", reflect.TypeOf(out))
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("the root objects must be structs, got %s", t)
+	}
+	return t, nil
+}
+
+// fieldSelection is a tree of the selected field paths. A nil selection selects every field.
+type fieldSelection map[string]fieldSelection
+
+func newFieldSelection(paths []string) fieldSelection {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	root := fieldSelection{}
+	for _, path := range paths {
+		s := root
+		for _, name := range strings.Split(path, ".") {
+			name = strings.TrimSpace(name)
+			next, ok := s[name]
+			if !ok || next == nil {
+				next = fieldSelection{}
+				s[name] = next
+			}
+			s = next
+		}
+	}
+	return root
+}
+
+// child returns the selection of the fields of the named field, and whether the field is selected at all.
+func (s fieldSelection) child(name string) (fieldSelection, bool) {
+	if s == nil {
+		return nil, true
+	}
+	child, ok := s[name]
+	if !ok {
+		return nil, false
+	}
+	if len(child) == 0 {
+		// A leaf path selects every field.
+		return nil, true
+	}
+	return child, true
+}
+
+type bulkQueryWriter struct {
+	// schema is the schema of the API version, or nil when there's no snapshot of it.
+	schema      *schema.Schema
+	connections int
+	// visiting holds the types on the current path, to report cycles instead of recursing forever.
+	visiting map[reflect.Type]bool
+}
+
+// writeNode writes the selection set of the node of a connection, which must select the `id` field.
+func (w *bulkQueryWriter) writeNode(sb *strings.Builder, t reflect.Type, path string, selection fieldSelection, depth int) error {
+	if _, ok := graphQLField(t, "id"); !ok {
+		return fmt.Errorf("%s: the nodes of %s must have an `id` field to be linked to their nested objects", path, t)
+	}
+	if selection != nil {
+		if _, ok := selection["id"]; !ok {
+			selection["id"] = fieldSelection{}
+		}
+	}
+	return w.writeSelectionSet(sb, t, path, selection, depth, false)
+}
+
+// writeSelectionSet writes the selected fields of the struct t. depth is the count of connections the struct
+// is nested in under the root one, and inList is set when it's an item of a list field.
+func (w *bulkQueryWriter) writeSelectionSet(sb *strings.Builder, t reflect.Type, path string, selection fieldSelection, depth int, inList bool) error {
+	if selection == nil {
+		// Only selecting every field can recurse forever, the selected paths end.
+		if w.visiting[t] {
+			return fmt.Errorf("%s: %s refers to itself, select its fields with WithBulkFields", path, t)
+		}
+		w.visiting[t] = true
+		defer delete(w.visiting, t)
+	}
+
+	fields := graphQLFields(t)
+	for name := range selection {
+		if _, ok := fields[name]; !ok {
+			return fmt.Errorf("%s: %s has no field `%s`", path, t, name)
+		}
+	}
+
+	var set strings.Builder
+	for _, f := range sortedGraphQLFields(fields) {
+		child, ok := selection.child(f.name)
+		if !ok {
+			continue
+		}
+		fieldPath := path + "." + f.name
+
+		if selection == nil && (isModelConnection(t, f) || w.requiresArguments(t, f)) {
+			continue
+		}
+
+		var field strings.Builder
+		field.WriteString(" ")
+		field.WriteString(f.query)
+		err := w.writeField(&field, f.typ, fieldPath, child, depth, inList)
+		if err != nil {
+			if selection != nil {
+				return err
+			}
+			// Selecting every field leaves out those that can't be queried, like the cyclic ones or the
+			// interfaces.
+			continue
+		}
+		set.WriteString(field.String())
+	}
+	if set.Len() == 0 {
+		return fmt.Errorf("%s: none of the fields of %s can be queried", path, t)
+	}
+
+	sb.WriteString("{")
+	sb.WriteString(set.String())
+	sb.WriteString(" }")
+	return nil
+}
+
+func (w *bulkQueryWriter) writeField(sb *strings.Builder, t reflect.Type, path string, selection fieldSelection, depth int, inList bool) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if isGraphQLScalar(t) {
+		if selection != nil {
+			return fmt.Errorf("%s: %s has no fields to select", path, t)
+		}
+		return nil
+	}
+
+	if edgeType, ok := connectionEdgeType(t); ok {
+		if inList {
+			return fmt.Errorf("%s: bulk queries don't support connections within list fields", path)
+		}
+		if depth+1 > schema.MaxBulkNestedConnections {
+			return fmt.Errorf("%s: bulk queries can nest connections at most %d levels deep", path, schema.MaxBulkNestedConnections)
+		}
+		w.connections++
+
+		node, _ := edgeType.FieldByName(nodeFieldName)
+		sb.WriteString(" { edges { node ")
+		err := w.writeConnectionNode(sb, node.Type, path, selection, depth+1)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(" } }")
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return w.writeField(sb, t.Elem(), path, selection, depth, true)
+	case reflect.Struct:
+		if _, ok := graphQLField(t, "id"); ok && selection == nil {
+			// Selecting every field of an object selects only the ID of the other resources it refers to.
+			selection = fieldSelection{"id": fieldSelection{}}
+		}
+		sb.WriteString(" ")
+		return w.writeSelectionSet(sb, t, path, selection, depth, inList)
+	case reflect.Interface:
+		return fmt.Errorf("%s: the fields of the interface %s can't be derived, use a struct", path, t)
+	default:
+		return fmt.Errorf("%s: %s can't be queried", path, t)
+	}
+}
+
+// writeConnectionNode writes the node of a nested connection. The fields of an interface node are selected
+// with an inline fragment for every type registered with RegisterBulkNodeType that implements it.
+func (w *bulkQueryWriter) writeConnectionNode(sb *strings.Builder, t reflect.Type, path string, selection fieldSelection, depth int) error {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		return w.writeNode(sb, t, path, selection, depth)
+	}
+	if t.Kind() != reflect.Interface {
+		return fmt.Errorf("%s: Node must be a pointer or an interface, got %s", path, t)
+	}
+
+	typenames, nodeTypes := registeredBulkNodeTypesOf(t)
+	if len(nodeTypes) == 0 {
+		return fmt.Errorf("%s: no type implementing %s is registered with RegisterBulkNodeType", path, t)
+	}
+
+	sb.WriteString("{ id")
+	matched := map[string]bool{}
+	for i, nodeType := range nodeTypes {
+		fields := graphQLFields(nodeType)
+		var typeSelection fieldSelection
+		if selection != nil {
+			typeSelection = fieldSelection{}
+			for name, child := range selection {
+				if _, ok := fields[name]; ok {
+					typeSelection[name] = child
+					matched[name] = true
+				}
+			}
+		}
+
+		sb.WriteString(" ... on ")
+		sb.WriteString(typenames[i])
+		sb.WriteString(" ")
+		err := w.writeNode(sb, nodeType, path+"."+typenames[i], typeSelection, depth)
+		if err != nil {
+			return err
+		}
+	}
+	sb.WriteString(" }")
+
+	for name := range selection {
+		if !matched[name] {
+			return fmt.Errorf("%s: no type implementing %s has a field `%s`", path, t, name)
+		}
+	}
+	return nil
+}
+
+// registeredBulkNodeTypesOf returns the registered node types implementing the interface, sorted by GraphQL type.
+func registeredBulkNodeTypesOf(iface reflect.Type) ([]string, []reflect.Type) {
+	bulkNodeTypesMu.RLock()
+	defer bulkNodeTypesMu.RUnlock()
+
+	var typenames []string
+	for typename, t := range bulkNodeTypes {
+		if reflect.PtrTo(t).Implements(iface) {
+			typenames = append(typenames, typename)
+		}
+	}
+	sort.Strings(typenames)
+
+	nodeTypes := make([]reflect.Type, len(typenames))
+	for i, typename := range typenames {
+		nodeTypes[i] = bulkNodeTypes[typename]
+	}
+	return typenames, nodeTypes
+}
+
+// requiresArguments reports whether the field of the struct t, when it's a model of the same name as its GraphQL
+// type, has required arguments that its query doesn't set.
+func (w *bulkQueryWriter) requiresArguments(t reflect.Type, f graphQLStructField) bool {
+	if w.schema == nil || f.query != f.name {
+		return false
+	}
+	typ := w.schema.Type(t.Name())
+	if typ == nil {
+		return false
+	}
+	def := typ.Field(f.name)
+	if def == nil {
+		return false
+	}
+	for _, arg := range def.Arguments {
+		if arg.Type.NonNull && arg.DefaultValue == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isModelConnection reports whether the field of the struct t is a connection of a model. The models hold every
+// connection of the API, more than a bulk query can, so they're only queried when selected.
+func isModelConnection(t reflect.Type, f graphQLStructField) bool {
+	if t.PkgPath() != modelPkgPath {
+		return false
+	}
+	typ := f.typ
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	_, ok := connectionEdgeType(typ)
+	return ok
+}
+
+// connectionEdgeType returns the type of the edges of t when it's a connection.
+func connectionEdgeType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	edges, ok := t.FieldByName(edgesFieldName)
+	if !ok || edges.Type.Kind() != reflect.Slice || edges.Type.Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+	if _, ok := edges.Type.Elem().FieldByName(nodeFieldName); !ok {
+		return nil, false
+	}
+	return edges.Type.Elem(), true
+}
+
+func isGraphQLScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Map:
+		return true
+	case reflect.Interface:
+		// e.g. a JSON scalar
+		return t.NumMethod() == 0
+	case reflect.Struct:
+		// e.g. time.Time or null.String
+		return reflect.PtrTo(t).Implements(textUnmarshalerType)
+	default:
+		return false
+	}
+}
+
+type graphQLStructField struct {
+	index int
+	name  string
+	// query is the field as written in the query, with its arguments if any.
+	query string
+	typ   reflect.Type
+}
+
+// graphQLFields returns the queryable fields of the struct t by GraphQL name, including the fields of embedded structs.
+func graphQLFields(t reflect.Type) map[string]graphQLStructField {
+	fields := map[string]graphQLStructField{}
+	collectGraphQLFields(t, fields, 0)
+	return fields
+}
+
+func collectGraphQLFields(t reflect.Type, fields map[string]graphQLStructField, offset int) int {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("json") == "" {
+			offset = collectGraphQLFields(f.Type, fields, offset)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		name, query, ok := graphQLFieldName(f)
+		if !ok {
+			continue
+		}
+		if _, ok := fields[name]; ok {
+			// Like encoding/json, the shallower field wins.
+			continue
+		}
+		fields[name] = graphQLStructField{index: offset, name: name, query: query, typ: f.Type}
+		offset++
+	}
+	return offset
+}
+
+func graphQLField(t reflect.Type, name string) (graphQLStructField, bool) {
+	f, ok := graphQLFields(t)[name]
+	return f, ok
+}
+
+func sortedGraphQLFields(fields map[string]graphQLStructField) []graphQLStructField {
+	sorted := make([]graphQLStructField, 0, len(fields))
+	for _, f := range fields {
+		sorted = append(sorted, f)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].index < sorted[j].index })
+	return sorted
+}
+
+// graphQLFieldName returns the GraphQL name of the struct field and the field as written in the query.
+func graphQLFieldName(f reflect.StructField) (string, string, bool) {
+	jsonName := strings.Split(f.Tag.Get("json"), ",")[0]
+	if jsonName == "-" {
+		return "", "", false
+	}
+
+	if query, ok := f.Tag.Lookup("graphql"); ok && query != "" {
+		name := query
+		if i := strings.IndexAny(name, "(@ "); i >= 0 {
+			name = name[:i]
+		}
+		if i := strings.Index(name, ":"); i >= 0 {
+			// An alias names the field in the result.
+			name = name[:i]
+		}
+		return name, query, true
+	}
+
+	name := jsonName
+	if name == "" {
+		name = lowerCamelCase(f.Name)
+	}
+	return name, name, true
+}
+
+// lowerCamelCase lowers the first word of a Go name, including a leading initialism, e.g. `ID` or `HTMLBody`.
+func lowerCamelCase(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// graphQLString quotes s as a GraphQL string value.
+func graphQLString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&sb, `\u%04x`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
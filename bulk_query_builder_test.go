@@ -0,0 +1,148 @@
+package shopify
+
+import (
+	"testing"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/sogko/go-shopify-graphql/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestBuildBulkQueryModelType(t *testing.T) {
+	q, err := BuildBulkQuery("products", []*model.Product{},
+		WithBulkFilter(`status:active title:"Shirt"`),
+		WithBulkFields("title", "variants.sku", "variants.media.image.url", "metafields.key"))
+	require.NoError(t, err)
+	assert.Equal(t, `{ products(query: "status:active title:\"Shirt\"") { edges { node { id metafields { edges { node { id key } } } title variants { edges { node { id media { edges { node { id ... on ExternalVideo { id } ... on MediaImage { id image { url } } ... on Model3d { id } ... on Video { id } } } } sku } } } } } } }`, q)
+
+	s, err := schema.Load(defaultShopifyAPIVersion)
+	require.NoError(t, err)
+	assert.NoError(t, s.ValidateQuery(q))
+}
+
+type bulkTestOrder struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	Note      null.String `json:"note"`
+	Internal  string      `json:"-"`
+	LineItems struct {
+		Edges []struct {
+			Node *struct {
+				ID       string `json:"id"`
+				Quantity int
+				Variant  *struct {
+					Sku string `json:"sku"`
+				}
+			}
+		}
+	}
+	Color *struct {
+		Value string `json:"value"`
+	} `graphql:"color: metafield(namespace: \"custom\", key: \"color\")"`
+}
+
+func TestBuildBulkQueryUserDefinedType(t *testing.T) {
+	q, err := BuildBulkQuery("orders", func(o *bulkTestOrder) error { return nil }, WithBulkFilter("created_at:>2023-01-01"))
+	require.NoError(t, err)
+	assert.Equal(t, `{ orders(query: "created_at:>2023-01-01") { edges { node { id name note lineItems { edges { node { id quantity variant { sku } } } } color: metafield(namespace: "custom", key: "color") { value } } } } }`, q)
+
+	s, err := schema.Load(defaultShopifyAPIVersion)
+	require.NoError(t, err)
+	assert.NoError(t, s.ValidateQuery(q))
+}
+
+func TestBuildBulkQueryUnsupportedShapes(t *testing.T) {
+	type node struct {
+		Title string
+	}
+	type connection struct {
+		Edges []struct{ Node *node }
+	}
+	type noNestedID struct {
+		ID       string
+		Variants connection
+	}
+	_, err := BuildBulkQuery("products", []noNestedID{}, WithBulkFields("variants.title"))
+	assert.EqualError(t, err, "products.variants: the nodes of shopify.node must have an `id` field to be linked to their nested objects")
+	// Selecting every field leaves it out instead.
+	q, err := BuildBulkQuery("products", []noNestedID{})
+	require.NoError(t, err)
+	assert.Equal(t, `{ products { edges { node { id } } } }`, q)
+
+	_, err = BuildBulkQuery("products", []*model.Product{}, WithBulkFields("variants.inventoryItem.inventoryLevels.location.metafields.key"))
+	assert.EqualError(t, err, "products.variants.inventoryItem.inventoryLevels.location.metafields: bulk queries can nest connections at most 2 levels deep")
+
+	type listed struct {
+		ID    string
+		Items []struct {
+			Variants *model.ProductVariantConnection
+		}
+	}
+	_, err = BuildBulkQuery("products", []listed{}, WithBulkFields("items.variants.id"))
+	assert.EqualError(t, err, "products.items.variants: bulk queries don't support connections within list fields")
+
+	_, err = BuildBulkQuery("products", []*model.Product{}, WithBulkFields("metafields.id", "variants.id", "images.id", "media.id", "collections.id"))
+	assert.EqualError(t, err, "bulk queries can hold at most 5 connections, got 6")
+
+	_, err = BuildBulkQuery("products", []*model.Product{}, WithBulkFields("colour"))
+	assert.EqualError(t, err, "products: model.Product has no field `colour`")
+
+	type cyclic struct {
+		ID     string
+		Parent *cyclic
+	}
+	q, err = BuildBulkQuery("products", []cyclic{})
+	require.NoError(t, err)
+	assert.Equal(t, `{ products { edges { node { id parent { id } } } } }`, q)
+	type loop struct {
+		Name string
+		Next *loop
+	}
+	type looped struct {
+		ID   string
+		Loop loop
+	}
+	q, err = BuildBulkQuery("products", []looped{})
+	require.NoError(t, err)
+	assert.Equal(t, `{ products { edges { node { id loop { name } } } } }`, q)
+	q, err = BuildBulkQuery("products", []cyclic{}, WithBulkFields("parent.parent.id"))
+	require.NoError(t, err)
+	assert.Equal(t, `{ products { edges { node { id parent { parent { id } } } } } }`, q)
+
+	_, err = BuildBulkQuery("products", model.Product{})
+	assert.EqualError(t, err, "the out arg is not a slice or a func(T) error, got model.Product")
+}
+
+func TestBuildBulkQueryModelsWithoutFields(t *testing.T) {
+	s, err := schema.Load(defaultShopifyAPIVersion)
+	require.NoError(t, err)
+
+	q, err := BuildBulkQuery("products", []*model.Product{})
+	require.NoError(t, err)
+	assert.Contains(t, q, " seo { description title } ")
+	// The connections are left out and the other resources are only linked by ID.
+	assert.NotContains(t, q, "variants")
+	assert.Contains(t, q, " featuredImage { id } ")
+	assert.NoError(t, s.ValidateQuery(q))
+	assert.NoError(t, schema.ValidateBulkQuery(q))
+
+	q, err = BuildBulkQuery("orders", []*model.Order{})
+	require.NoError(t, err)
+	assert.Contains(t, q, " customer { id } ")
+	assert.NotContains(t, q, "lineItems")
+	assert.NoError(t, s.ValidateQuery(q))
+	assert.NoError(t, schema.ValidateBulkQuery(q))
+}
+
+func TestGraphQLString(t *testing.T) {
+	assert.Equal(t, `"tag:\"a\\b\"\n\u0001é"`, graphQLString("tag:\"a\\b\"\n\x01é"))
+}
+
+func TestLowerCamelCase(t *testing.T) {
+	assert.Equal(t, "id", lowerCamelCase("ID"))
+	assert.Equal(t, "htmlBody", lowerCamelCase("HTMLBody"))
+	assert.Equal(t, "lineItems", lowerCamelCase("LineItems"))
+	assert.Equal(t, "x", lowerCamelCase("X"))
+}
@@ -0,0 +1,368 @@
+package shopify
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+var (
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	bulkObjectType    = reflect.TypeOf(BulkObject{})
+	bulkObjectMapType = reflect.TypeOf(map[string]interface{}{})
+)
+
+// bulkResultDecoder reads the JSONL result of a bulk query and assembles one root object at a time.
+// Shopify writes every nested object after its parent, so only the objects of the current root are held
+// in memory: once the next root line is read, the current root is complete.
+type bulkResultDecoder struct {
+	lines    *bulkLineReader
+	itemType reflect.Type
+	json     jsoniter.API
+
+	// nodes holds the objects of the current root by ID, so nested objects can be attached to their parent.
+	nodes map[string]reflect.Value
+	// connections caches the connection of a parent type that holds the nested objects of a GraphQL type.
+	connections map[bulkConnectionKey]*bulkConnection
+}
+
+func newBulkResultDecoder(r io.Reader, itemType reflect.Type) *bulkResultDecoder {
+	return &bulkResultDecoder{
+		lines:       newBulkLineReader(r),
+		itemType:    itemType,
+		json:        jsoniter.ConfigFastest,
+		nodes:       make(map[string]reflect.Value),
+		connections: make(map[bulkConnectionKey]*bulkConnection),
+	}
+}
+
+// Decode returns a pointer to the next root object with all of its nested connections attached,
+// or io.EOF when there are no more objects.
+func (d *bulkResultDecoder) Decode() (reflect.Value, error) {
+	var root reflect.Value
+	for {
+		line, err := d.lines.read()
+		if errors.Is(err, io.EOF) {
+			if root.IsValid() {
+				return root, nil
+			}
+			return reflect.Value{}, io.EOF
+		}
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("reading the result file: %w", err)
+		}
+
+		keys, err := d.scanKeys(line)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if keys.ParentID != "" {
+			if !root.IsValid() {
+				return reflect.Value{}, fmt.Errorf("object with __parentId `%s` precedes its parent", keys.ParentID)
+			}
+			err = d.attach(keys, line)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			continue
+		}
+
+		if root.IsValid() {
+			d.lines.unread(line)
+			return root, nil
+		}
+
+		root = reflect.New(d.itemType)
+		err = d.json.Unmarshal(line, root.Interface())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("unmarshalling: %w", err)
+		}
+
+		for id := range d.nodes {
+			delete(d.nodes, id)
+		}
+		if node, id, ok := nodeWithID(root); ok {
+			d.nodes[id] = node
+		}
+	}
+}
+
+// bulkLineKeys are the fields placing the object of a line in the tree of its root object. Decoding them alone
+// skips the other fields without allocating.
+type bulkLineKeys struct {
+	ID       string `json:"id"`
+	ParentID string `json:"__parentId"`
+	Typename string `json:"__typename"`
+}
+
+func (d *bulkResultDecoder) scanKeys(line []byte) (bulkLineKeys, error) {
+	keys := bulkLineKeys{}
+	err := d.json.Unmarshal(line, &keys)
+	if err != nil {
+		return keys, fmt.Errorf("unmarshalling: %w", err)
+	}
+	return keys, nil
+}
+
+// bulkLineReader reads the non-empty lines of a JSONL file.
+type bulkLineReader struct {
+	reader *bufio.Reader
+	// next is a line read ahead, e.g. the next root line read while assembling the previous root object.
+	next []byte
+}
+
+func newBulkLineReader(r io.Reader) *bulkLineReader {
+	return &bulkLineReader{reader: bufio.NewReader(r)}
+}
+
+func (l *bulkLineReader) read() ([]byte, error) {
+	if l.next != nil {
+		line := l.next
+		l.next = nil
+		return line, nil
+	}
+
+	for {
+		line, err := l.reader.ReadBytes('\n')
+		if len(line) > 0 && (err == nil || errors.Is(err, io.EOF)) {
+			if len(line) == 1 && line[0] == '\n' {
+				continue
+			}
+			return line, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// unread returns the line to be read again by the next read.
+func (l *bulkLineReader) unread(line []byte) {
+	l.next = line
+}
+
+// attach adds the object on the line to the connection of its parent, which must be part of the current root object.
+func (d *bulkResultDecoder) attach(keys bulkLineKeys, line []byte) error {
+	parent, ok := d.nodes[keys.ParentID]
+	if !ok {
+		return fmt.Errorf("parent `%s` not found, the root objects must query the `id` field", keys.ParentID)
+	}
+	if keys.ID == "" {
+		return fmt.Errorf("The connection type must query the `id` field")
+	}
+	typename, err := bulkNodeTypename(keys.Typename, keys.ID)
+	if err != nil {
+		return err
+	}
+	conn, err := d.connection(parent.Elem().Type(), typename)
+	if err != nil {
+		return err
+	}
+
+	node := reflect.New(conn.nodeType)
+	err = d.json.Unmarshal(line, node.Interface())
+	if err != nil {
+		return fmt.Errorf("unmarshalling: %w", err)
+	}
+
+	connectionField := parent.Elem().FieldByIndex(conn.index)
+	if connectionField.Kind() == reflect.Ptr {
+		if connectionField.IsNil() {
+			connectionField.Set(reflect.New(connectionField.Type().Elem()))
+		}
+		connectionField = connectionField.Elem()
+	}
+	edges := connectionField.FieldByIndex(conn.edgesIndex)
+	appendEdge(edges).FieldByIndex(conn.nodeIndex).Set(node)
+
+	d.nodes[keys.ID] = node
+
+	return nil
+}
+
+// appendEdge grows the slice of edges by one zero edge in place and returns it, doubling the capacity when it's
+// full as append does.
+func appendEdge(edges reflect.Value) reflect.Value {
+	n := edges.Len()
+	if n == edges.Cap() {
+		grown := reflect.MakeSlice(edges.Type(), n, 2*n+4)
+		reflect.Copy(grown, edges)
+		edges.Set(grown)
+	}
+	edges.SetLen(n + 1)
+	return edges.Index(n)
+}
+
+func (d *bulkResultDecoder) connection(parentType reflect.Type, typename string) (*bulkConnection, error) {
+	key := bulkConnectionKey{parentType: parentType, typename: typename}
+	if conn, ok := d.connections[key]; ok {
+		return conn, nil
+	}
+
+	conn, err := findBulkConnection(parentType, typename)
+	if err != nil {
+		return nil, err
+	}
+	d.connections[key] = conn
+
+	return conn, nil
+}
+
+// nodeWithID returns the object behind v, unwrapping a `Node` field, and its ID.
+func nodeWithID(v reflect.Value) (reflect.Value, string, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, "", false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, "", false
+	}
+
+	if node := v.FieldByName(nodeFieldName); node.IsValid() {
+		return nodeWithID(node)
+	}
+
+	id := v.FieldByName("ID")
+	if !id.IsValid() || id.Kind() != reflect.String || !v.CanAddr() {
+		return reflect.Value{}, "", false
+	}
+
+	return v.Addr(), id.String(), true
+}
+
+// parseBulkQueryResult appends every root object of the result to the slice behind out and returns their count.
+func parseBulkQueryResult(r io.Reader, out interface{}) (int, error) {
+	if reflect.TypeOf(out).Kind() != reflect.Ptr {
+		return 0, fmt.Errorf("the out arg is not a pointer")
+	}
+
+	outValue := reflect.ValueOf(out)
+	outSlice := outValue.Elem()
+	if outSlice.Kind() != reflect.Slice {
+		return 0, fmt.Errorf("the out arg is not a pointer to a slice interface")
+	}
+
+	sliceItemType := outSlice.Type().Elem() // slice item type
+	sliceItemKind := sliceItemType.Kind()
+	itemType := sliceItemType // slice item underlying type
+	if sliceItemKind == reflect.Ptr {
+		itemType = itemType.Elem()
+	}
+
+	return readBulkQueryResult(r, itemType, func(item reflect.Value) error {
+		if sliceItemKind == reflect.Ptr {
+			outSlice.Set(reflect.Append(outSlice, item))
+		} else {
+			outSlice.Set(reflect.Append(outSlice, item.Elem()))
+		}
+		return nil
+	})
+}
+
+// streamBulkQueryResult calls fn, a `func(T) error` or `func(*T) error`, with every root object of the result
+// and returns the count of objects fn accepted.
+func streamBulkQueryResult(r io.Reader, fn interface{}) (int, error) {
+	fnValue := reflect.ValueOf(fn)
+	fnType := fnValue.Type()
+	if fnType.Kind() != reflect.Func || fnType.NumIn() != 1 || fnType.NumOut() != 1 || fnType.Out(0) != errorType {
+		return 0, fmt.Errorf("the fn arg is not a func(T) error, got %s", fnType)
+	}
+
+	argType := fnType.In(0)
+	itemType := argType
+	if argType.Kind() == reflect.Ptr {
+		itemType = argType.Elem()
+	}
+
+	return readBulkQueryResult(r, itemType, func(item reflect.Value) error {
+		if argType.Kind() != reflect.Ptr {
+			item = item.Elem()
+		}
+		err, _ := fnValue.Call([]reflect.Value{item})[0].Interface().(error)
+		return err
+	})
+}
+
+func readBulkQueryResult(r io.Reader, itemType reflect.Type, yield func(reflect.Value) error) (int, error) {
+	if itemType == bulkObjectType || itemType == bulkObjectMapType {
+		return readBulkObjects(r, itemType, yield)
+	}
+
+	d := newBulkResultDecoder(r, itemType)
+	count := 0
+	for {
+		item, err := d.Decode()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+
+		err = yield(item)
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+// readBulkObjects yields a pointer to every root object of the result as a BulkObject or as its map.
+func readBulkObjects(r io.Reader, itemType reflect.Type, yield func(reflect.Value) error) (int, error) {
+	objects := newBulkObjectReader(r)
+	count := 0
+	for {
+		obj, err := objects.Read()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+
+		item := reflect.ValueOf(obj)
+		if itemType == bulkObjectMapType {
+			m := obj.Map()
+			item = reflect.ValueOf(&m)
+		}
+		err = yield(item)
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+// completeLinesReader reads the lines of r, dropping the last line when it's cut short, as in the partial data
+// of a failed bulk operation.
+type completeLinesReader struct {
+	r       *bufio.Reader
+	pending []byte
+}
+
+func newCompleteLinesReader(r io.Reader) *completeLinesReader {
+	return &completeLinesReader{r: bufio.NewReader(r)}
+}
+
+func (c *completeLinesReader) Read(p []byte) (int, error) {
+	if len(c.pending) == 0 {
+		line, err := c.r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return 0, io.EOF
+		}
+		if err != nil {
+			return 0, err
+		}
+		c.pending = line
+	}
+
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
@@ -0,0 +1,381 @@
+package shopify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bulkResultFixture = `{"id":"gid://shopify/Product/1","title":"Shirt"}
+{"id":"gid://shopify/ProductVariant/11","sku":"SHIRT-S","__parentId":"gid://shopify/Product/1"}
+{"id":"gid://shopify/MediaImage/111","__parentId":"gid://shopify/ProductVariant/11"}
+{"id":"gid://shopify/ProductVariant/12","sku":"SHIRT-M","__parentId":"gid://shopify/Product/1"}
+{"id":"gid://shopify/Product/2","title":"Hat"}
+{"id":"gid://shopify/Product/3","title":"Socks"}
+{"id":"gid://shopify/ProductVariant/31","sku":"SOCKS","__parentId":"gid://shopify/Product/3"}`
+
+func TestParseBulkQueryResult(t *testing.T) {
+	res := []*model.Product{}
+	_, err := parseBulkQueryResult(strings.NewReader(bulkResultFixture), &res)
+	require.NoError(t, err)
+	require.Len(t, res, 3)
+
+	assert.Equal(t, "Shirt", res[0].Title)
+	require.Len(t, res[0].Variants.Edges, 2)
+	assert.Equal(t, "SHIRT-S", *res[0].Variants.Edges[0].Node.Sku)
+	require.Len(t, res[0].Variants.Edges[0].Node.Media.Edges, 1)
+	assert.Equal(t, "gid://shopify/MediaImage/111", res[0].Variants.Edges[0].Node.Media.Edges[0].Node.(*model.MediaImage).ID)
+	assert.Nil(t, res[1].Variants)
+	require.Len(t, res[2].Variants.Edges, 1)
+	assert.Equal(t, "SOCKS", *res[2].Variants.Edges[0].Node.Sku)
+}
+
+func TestStreamBulkQueryResult(t *testing.T) {
+	var titles []string
+	var variants []int
+	n, err := streamBulkQueryResult(strings.NewReader(bulkResultFixture), func(p model.Product) error {
+		titles = append(titles, p.Title)
+		if p.Variants == nil {
+			variants = append(variants, 0)
+		} else {
+			variants = append(variants, len(p.Variants.Edges))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Shirt", "Hat", "Socks"}, titles)
+	assert.Equal(t, []int{2, 0, 1}, variants)
+	assert.Equal(t, 3, n)
+
+	errStop := errors.New("stop")
+	calls := 0
+	n, err = streamBulkQueryResult(strings.NewReader(bulkResultFixture), func(p *model.Product) error {
+		calls++
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 0, n)
+
+	_, err = streamBulkQueryResult(strings.NewReader(bulkResultFixture), func(p *model.Product) {})
+	assert.EqualError(t, err, "the fn arg is not a func(T) error, got func(*model.Product)")
+}
+
+func TestStreamBulkQueryResultOrphan(t *testing.T) {
+	fixture := `{"id":"gid://shopify/Product/1"}
+{"id":"gid://shopify/Product/2"}
+{"id":"gid://shopify/ProductVariant/11","__parentId":"gid://shopify/Product/1"}
+`
+
+	_, err := streamBulkQueryResult(strings.NewReader(fixture), func(p *model.Product) error { return nil })
+	assert.EqualError(t, err, "parent `gid://shopify/Product/1` not found, the root objects must query the `id` field")
+}
+
+func TestParseBulkQueryResultAnyConnection(t *testing.T) {
+	fixture := `{"id":"gid://shopify/Collection/1","title":"Summer"}
+{"id":"gid://shopify/Product/11","title":"Shirt","__parentId":"gid://shopify/Collection/1"}
+{"id":"gid://shopify/Video/111","__parentId":"gid://shopify/Product/11"}
+{"id":"gid://shopify/InventoryItem/2"}
+{"id":"gid://shopify/InventoryLevel/21?inventory_item_id=2","__parentId":"gid://shopify/InventoryItem/2"}
+`
+
+	type collectionOrItem struct {
+		model.Collection
+		InventoryLevels *model.InventoryLevelConnection
+	}
+	res := []collectionOrItem{}
+	_, err := parseBulkQueryResult(strings.NewReader(fixture), &res)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	require.Len(t, res[0].Products.Edges, 1)
+	assert.Equal(t, "Shirt", res[0].Products.Edges[0].Node.Title)
+	require.Len(t, res[0].Products.Edges[0].Node.Media.Edges, 1)
+	assert.Equal(t, "gid://shopify/Video/111", res[0].Products.Edges[0].Node.Media.Edges[0].Node.(*model.Video).ID)
+	require.Len(t, res[1].InventoryLevels.Edges, 1)
+	assert.Equal(t, "gid://shopify/InventoryLevel/21?inventory_item_id=2", res[1].InventoryLevels.Edges[0].Node.ID)
+}
+
+type bulkTestPart struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bulkTestPartConnection struct {
+	Edges []struct {
+		Node *bulkTestPart `json:"node"`
+	} `json:"edges"`
+}
+
+func TestParseBulkQueryResultUserDefinedTypes(t *testing.T) {
+	fixture := `{"id":"gid://shopify/Product/1"}
+{"id":"gid://shopify/ProductVariant/11","name":"Small","__parentId":"gid://shopify/Product/1"}
+{"id":"gid://shopify/Metafield/12","__typename":"Metafield","name":"Cotton","__parentId":"gid://shopify/Product/1"}
+`
+
+	type product struct {
+		ID         string                  `json:"id"`
+		Sizes      bulkTestPartConnection  `bulk:"ProductVariant"`
+		Metafields *bulkTestPartConnection `bulk:"Metafield"`
+	}
+	res := []product{}
+	_, err := parseBulkQueryResult(strings.NewReader(fixture), &res)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Len(t, res[0].Sizes.Edges, 1)
+	assert.Equal(t, "Small", res[0].Sizes.Edges[0].Node.Name)
+	require.Len(t, res[0].Metafields.Edges, 1)
+	assert.Equal(t, "Cotton", res[0].Metafields.Edges[0].Node.Name)
+
+	type untagged struct {
+		ID    string `json:"id"`
+		Sizes bulkTestPartConnection
+	}
+	_, err = parseBulkQueryResult(strings.NewReader(fixture), &[]untagged{})
+	assert.EqualError(t, err, "no connection of ProductVariant nodes is defined on the parent type shopify.untagged")
+
+	type ambiguous struct {
+		ID       string `json:"id"`
+		Variants *model.ProductVariantConnection
+		Others   *model.ProductVariantConnection
+	}
+	_, err = parseBulkQueryResult(strings.NewReader(fixture), &[]ambiguous{})
+	assert.EqualError(t, err, "connections Variants, Others of the parent type shopify.ambiguous all hold ProductVariant nodes, tag the one queried with `bulk:\"ProductVariant\"`")
+
+	type tagged struct {
+		ID       string `json:"id"`
+		Variants *model.ProductVariantConnection
+		Others   *model.ProductVariantConnection `bulk:"ProductVariant"`
+	}
+	tres := []tagged{}
+	_, err = parseBulkQueryResult(strings.NewReader(fixture[:strings.Index(fixture, `{"id":"gid://shopify/Metafield`)]), &tres)
+	require.NoError(t, err)
+	assert.Nil(t, tres[0].Variants)
+	require.Len(t, tres[0].Others.Edges, 1)
+}
+
+func TestParseBulkQueryResultOrders(t *testing.T) {
+	fixture := `{"id":"gid://shopify/Order/1","name":"#1001"}
+{"id":"gid://shopify/LineItem/11","sku":"SHIRT-S","__parentId":"gid://shopify/Order/1"}
+{"id":"gid://shopify/LineItem/12","sku":"SHIRT-M","__parentId":"gid://shopify/Order/1"}
+{"id":"gid://shopify/Order/2","name":"#1002"}
+`
+
+	res := []*model.Order{}
+	_, err := parseBulkQueryResult(strings.NewReader(fixture), &res)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.Len(t, res[0].LineItems.Edges, 2)
+	assert.Equal(t, "SHIRT-M", *res[0].LineItems.Edges[1].Node.Sku)
+	assert.Nil(t, res[0].NonFulfillableLineItems)
+	assert.Nil(t, res[1].LineItems)
+}
+
+func TestCompleteLinesReader(t *testing.T) {
+	b, err := io.ReadAll(newCompleteLinesReader(strings.NewReader("{\"id\":\"1\"}\n{\"id\":\"2\"}\n{\"id\":")))
+	require.NoError(t, err)
+	assert.Equal(t, "{\"id\":\"1\"}\n{\"id\":\"2\"}\n", string(b))
+
+	b, err = io.ReadAll(newCompleteLinesReader(strings.NewReader("{\"id\":\"1\"}\n")))
+	require.NoError(t, err)
+	assert.Equal(t, "{\"id\":\"1\"}\n", string(b))
+}
+
+func TestResumeBulkQueryPartialData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(bulkResultFixture[:strings.Index(bulkResultFixture, `{"id":"gid://shopify/Product/3"`)+10]))
+	}))
+	defer srv.Close()
+
+	errorCode := model.BulkOperationErrorCodeTimeout
+	gql := &bulkOperationGraphQL{op: model.BulkOperation{
+		ID:             "gid://shopify/BulkOperation/1",
+		Status:         model.BulkOperationStatusFailed,
+		ErrorCode:      &errorCode,
+		PartialDataURL: &srv.URL,
+	}}
+	client := NewClient("test", WithGraphQLClient(gql))
+
+	res := []*model.Product{}
+	err := client.BulkOperation.ResumeBulkQuery(context.Background(), gql.op.ID, &res)
+	var opErr *BulkOperationError
+	require.ErrorAs(t, err, &opErr)
+	assert.False(t, opErr.PartialData)
+	assert.Empty(t, res)
+
+	err = client.BulkOperation.ResumeBulkQuery(context.Background(), gql.op.ID, &res, WithPartialData())
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, "Bulk operation didn't complete, status=FAILED, error_code=TIMEOUT, recovered 2 objects from the partial data", err.Error())
+	assert.Equal(t, 2, opErr.Recovered)
+	require.Len(t, res, 2)
+	assert.Len(t, res[0].Variants.Edges, 2)
+	assert.Equal(t, "Hat", res[1].Title)
+}
+
+func TestParseBulkQueryResultDeepNesting(t *testing.T) {
+	type Metafield struct {
+		ID    string `json:"id"`
+		Value string `json:"value"`
+	}
+	type ProductVariant struct {
+		ID         string `json:"id"`
+		Metafields struct {
+			Edges []struct{ Node *Metafield }
+		}
+	}
+	type Product struct {
+		ID       string `json:"id"`
+		Variants struct {
+			Edges []struct{ Node *ProductVariant }
+		}
+	}
+	type Collection struct {
+		ID       string `json:"id"`
+		Products *struct {
+			Edges []struct{ Node *Product }
+		}
+	}
+	fixture := `{"id":"gid://shopify/Collection/1"}
+{"id":"gid://shopify/Product/1","__parentId":"gid://shopify/Collection/1"}
+{"id":"gid://shopify/ProductVariant/11","__parentId":"gid://shopify/Product/1"}
+{"id":"gid://shopify/Metafield/111","value":"a","__parentId":"gid://shopify/ProductVariant/11"}
+{"id":"gid://shopify/Metafield/112","value":"b","__parentId":"gid://shopify/ProductVariant/11"}
+{"id":"gid://shopify/ProductVariant/12","__parentId":"gid://shopify/Product/1"}
+{"id":"gid://shopify/Product/2","__parentId":"gid://shopify/Collection/1"}
+{"id":"gid://shopify/ProductVariant/21","__parentId":"gid://shopify/Product/2"}
+{"id":"gid://shopify/Metafield/211","value":"c","__parentId":"gid://shopify/ProductVariant/21"}
+`
+
+	var res []Collection
+	n, err := parseBulkQueryResult(strings.NewReader(fixture), &res)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, res, 1)
+
+	products := res[0].Products.Edges
+	require.Len(t, products, 2)
+	require.Len(t, products[0].Node.Variants.Edges, 2)
+	assert.Equal(t, []string{"a", "b"}, []string{
+		products[0].Node.Variants.Edges[0].Node.Metafields.Edges[0].Node.Value,
+		products[0].Node.Variants.Edges[0].Node.Metafields.Edges[1].Node.Value,
+	})
+	assert.Empty(t, products[0].Node.Variants.Edges[1].Node.Metafields.Edges)
+	require.Len(t, products[1].Node.Variants.Edges, 1)
+	assert.Equal(t, "c", products[1].Node.Variants.Edges[0].Node.Metafields.Edges[0].Node.Value)
+}
+
+// benchFullBulkResult runs BenchmarkParseBulkQueryResult on a result as large as a full catalog, generated while
+// it's parsed as it doesn't fit in memory.
+var benchFullBulkResult = flag.Bool("bench-full-bulk-result", false, "benchmark the parsing of a bulk result of 100k products with 100 variants each")
+
+// bulkResultBenchFixture generates the JSONL result of a query of products with their variants and the metafields
+// of the variants.
+func bulkResultBenchFixture(products, variants, metafields int) []byte {
+	var buf bytes.Buffer
+	writeBulkResultBenchFixture(&buf, products, variants, metafields)
+	return buf.Bytes()
+}
+
+// writeBulkResultBenchFixture writes the lines without allocating, to keep allocs/op to those of the parsing.
+func writeBulkResultBenchFixture(w io.Writer, products, variants, metafields int) {
+	var line []byte
+	id := 0
+	for p := 0; p < products; p++ {
+		id++
+		productID := id
+		line = append(line[:0], `{"id":"gid://shopify/Product/`...)
+		line = strconv.AppendInt(line, int64(productID), 10)
+		line = append(line, `","title":"Product `...)
+		line = strconv.AppendInt(line, int64(p), 10)
+		line = append(line, `","handle":"product-`...)
+		line = strconv.AppendInt(line, int64(p), 10)
+		line = append(line, "\",\"tags\":[\"a\",\"b\"]}\n"...)
+		w.Write(line)
+		for v := 0; v < variants; v++ {
+			id++
+			variantID := id
+			line = append(line[:0], `{"id":"gid://shopify/ProductVariant/`...)
+			line = strconv.AppendInt(line, int64(variantID), 10)
+			line = append(line, `","sku":"SKU-`...)
+			line = strconv.AppendInt(line, int64(variantID), 10)
+			line = append(line, `","price":"9.99","inventoryQuantity":`...)
+			line = strconv.AppendInt(line, int64(v), 10)
+			line = append(line, `,"__parentId":"gid://shopify/Product/`...)
+			line = strconv.AppendInt(line, int64(productID), 10)
+			line = append(line, "\"}\n"...)
+			w.Write(line)
+			for m := 0; m < metafields; m++ {
+				id++
+				line = append(line[:0], `{"id":"gid://shopify/Metafield/`...)
+				line = strconv.AppendInt(line, int64(id), 10)
+				line = append(line, `","namespace":"custom","key":"key`...)
+				line = strconv.AppendInt(line, int64(m), 10)
+				line = append(line, `","value":"value","__parentId":"gid://shopify/ProductVariant/`...)
+				line = strconv.AppendInt(line, int64(variantID), 10)
+				line = append(line, "\"}\n"...)
+				w.Write(line)
+			}
+		}
+	}
+}
+
+func BenchmarkParseBulkQueryResult(b *testing.B) {
+	sizes := []struct {
+		products, variants, metafields int
+	}{
+		{products: 1000, variants: 10, metafields: 0},
+		{products: 100, variants: 100, metafields: 2},
+		{products: 10, variants: 100, metafields: 20},
+	}
+	for _, size := range sizes {
+		fixture := bulkResultBenchFixture(size.products, size.variants, size.metafields)
+		name := fmt.Sprintf("products=%d/variants=%d/metafields=%d", size.products, size.variants, size.metafields)
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(fixture)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				n, err := streamBulkQueryResult(bytes.NewReader(fixture), func(p *model.Product) error { return nil })
+				if err != nil || n != size.products {
+					b.Fatalf("parsed %d products: %v", n, err)
+				}
+			}
+		})
+	}
+
+	const products, variants, metafields = 100000, 100, 2
+	b.Run(fmt.Sprintf("products=%d/variants=%d/metafields=%d", products, variants, metafields), func(b *testing.B) {
+		if !*benchFullBulkResult {
+			b.Skip("run with -bench-full-bulk-result")
+		}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			r, w := io.Pipe()
+			go func() {
+				bw := bufio.NewWriter(w)
+				writeBulkResultBenchFixture(bw, products, variants, metafields)
+				w.CloseWithError(bw.Flush())
+			}()
+
+			var size byteCounter
+			n, err := streamBulkQueryResult(io.TeeReader(r, &size), func(p *model.Product) error { return nil })
+			r.Close()
+			if err != nil || n != products {
+				b.Fatalf("parsed %d products: %v", n, err)
+			}
+			b.SetBytes(int64(size))
+		}
+	})
+}
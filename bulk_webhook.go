@@ -0,0 +1,189 @@
+package shopify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	log "github.com/sirupsen/logrus"
+	"github.com/sogko/go-shopify-graphql/model"
+)
+
+const (
+	// defaultWebhookFallbackInterval is the interval of the polls made while waiting on the webhook, in case
+	// a delivery is lost.
+	defaultWebhookFallbackInterval = 5 * time.Minute
+	// maxWebhookBodySize caps the size of the deliveries read by the handler.
+	maxWebhookBodySize = 1 << 20
+
+	bulkOperationsFinishTopicHeader = "bulk_operations/finish"
+)
+
+// BulkOperationsFinishEvent is the payload of a BULK_OPERATIONS_FINISH webhook delivery.
+type BulkOperationsFinishEvent struct {
+	AdminGraphqlAPIID string  `json:"admin_graphql_api_id"`
+	CompletedAt       string  `json:"completed_at"`
+	CreatedAt         string  `json:"created_at"`
+	ErrorCode         *string `json:"error_code"`
+	Status            string  `json:"status"`
+	Type              string  `json:"type"`
+}
+
+// WithWebhookFallback sets the interval of the polls made while waiting for the BULK_OPERATIONS_FINISH delivery
+// of the operation, in case it's lost, see SubscribeBulkOperationsFinish. A poll interval set by the caller takes
+// over the fallback.
+func WithWebhookFallback(interval time.Duration) BulkQueryOption {
+	return func(o *bulkQueryOptions) {
+		o.webhookFallbackInterval = interval
+	}
+}
+
+// bulkFinishNotifier passes the BULK_OPERATIONS_FINISH deliveries to the callers waiting on their operation.
+type bulkFinishNotifier struct {
+	mu      sync.Mutex
+	enabled bool
+	waiters map[string][]chan struct{}
+}
+
+func (n *bulkFinishNotifier) enable() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.enabled = true
+}
+
+// wait returns the channel signalled when the operation finishes, or nil when the webhook isn't subscribed, and
+// the function to stop waiting.
+func (n *bulkFinishNotifier) wait(id string) (<-chan struct{}, func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !n.enabled {
+		return nil, func() {}
+	}
+
+	if n.waiters == nil {
+		n.waiters = map[string][]chan struct{}{}
+	}
+	ch := make(chan struct{}, 1)
+	n.waiters[id] = append(n.waiters[id], ch)
+
+	return ch, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		waiters := n.waiters[id]
+		for i, w := range waiters {
+			if w == ch {
+				waiters = append(waiters[:i], waiters[i+1:]...)
+				break
+			}
+		}
+		if len(waiters) == 0 {
+			delete(n.waiters, id)
+		} else {
+			n.waiters[id] = waiters
+		}
+	}
+}
+
+func (n *bulkFinishNotifier) notify(id string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, ch := range n.waiters[id] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// SubscribeBulkOperationsFinish registers a BULK_OPERATIONS_FINISH webhook delivering to callbackURL, unless one
+// is registered already. The bulk operations are then awaited on the deliveries passed to
+// BulkOperationsFinishHandler, polling only every WithWebhookFallback interval in case a delivery is lost.
+func (s *BulkOperationServiceOp) SubscribeBulkOperationsFinish(ctx context.Context, callbackURL string) (*model.WebhookSubscription, error) {
+	subscriptions, err := s.client.Webhook.ListWebhookSubscriptions(ctx, []model.WebhookSubscriptionTopic{model.WebhookSubscriptionTopicBulkOperationsFinish})
+	if err != nil {
+		return nil, fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+
+	var subscription *model.WebhookSubscription
+	for _, sub := range subscriptions {
+		if sub.CallbackURL == callbackURL {
+			subscription = sub
+			break
+		}
+	}
+
+	if subscription == nil {
+		format := model.WebhookSubscriptionFormatJSON
+		subscription, err = s.client.Webhook.CreateWebhookSubscription(ctx, model.WebhookSubscriptionTopicBulkOperationsFinish, model.WebhookSubscriptionInput{
+			CallbackURL: &callbackURL,
+			Format:      &format,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create webhook subscription: %w", err)
+		}
+	}
+
+	s.finish.enable()
+	return subscription, nil
+}
+
+// BulkOperationsFinishHandler returns the handler of the BULK_OPERATIONS_FINISH deliveries, which resumes the
+// callers waiting on the finished operation. The deliveries are verified with the secret of the app, without which
+// every delivery is rejected.
+func (s *BulkOperationServiceOp) BulkOperationsFinishHandler(secret string) http.Handler {
+	if secret == "" {
+		log.Errorf("BulkOperationsFinishHandler has no secret to verify the deliveries, they are all rejected")
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if secret == "" {
+			http.Error(w, "no webhook secret", http.StatusInternalServerError)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+		if err != nil {
+			http.Error(w, "read body", http.StatusBadRequest)
+			return
+		}
+		if !validWebhookHMAC(body, r.Header.Get("X-Shopify-Hmac-Sha256"), secret) {
+			http.Error(w, "invalid HMAC", http.StatusUnauthorized)
+			return
+		}
+		if topic := r.Header.Get("X-Shopify-Topic"); topic != "" && topic != bulkOperationsFinishTopicHeader {
+			http.Error(w, fmt.Sprintf("unexpected topic %s", topic), http.StatusBadRequest)
+			return
+		}
+
+		event := BulkOperationsFinishEvent{}
+		err = json.Unmarshal(body, &event)
+		if err != nil || event.AdminGraphqlAPIID == "" {
+			http.Error(w, "malformed payload", http.StatusBadRequest)
+			return
+		}
+
+		log.Debugf("Bulk operation %s finished, status=%s", event.AdminGraphqlAPIID, event.Status)
+		s.finish.notify(event.AdminGraphqlAPIID)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// validWebhookHMAC verifies the base64 encoded HMAC-SHA256 of the body Shopify signs the deliveries with.
+func validWebhookHMAC(body []byte, signature string, secret string) bool {
+	expected, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,177 @@
+package shopify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bulkOperationsFinishPayload = `{"admin_graphql_api_id":"gid://shopify/BulkOperation/1","completed_at":"2023-04-01T10:00:00-04:00","created_at":"2023-04-01T09:00:00-04:00","error_code":null,"status":"completed","type":"query"}`
+
+func signWebhook(body, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func deliverBulkOperationsFinish(handler http.Handler, body, signature, topic string) int {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/bulk", strings.NewReader(body))
+	req.Header.Set("X-Shopify-Hmac-Sha256", signature)
+	req.Header.Set("X-Shopify-Topic", topic)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestBulkOperationsFinishHandler(t *testing.T) {
+	s := NewClient("test").BulkOperation.(*BulkOperationServiceOp)
+	s.finish.enable()
+	finished, stop := s.finish.wait("gid://shopify/BulkOperation/1")
+	defer stop()
+	handler := s.BulkOperationsFinishHandler("secret")
+
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		topic     string
+		want      int
+	}{{
+		name:      "invalid signature",
+		body:      bulkOperationsFinishPayload,
+		signature: signWebhook(bulkOperationsFinishPayload, "other"),
+		topic:     "bulk_operations/finish",
+		want:      http.StatusUnauthorized,
+	}, {
+		name:      "other topic",
+		body:      bulkOperationsFinishPayload,
+		signature: signWebhook(bulkOperationsFinishPayload, "secret"),
+		topic:     "products/update",
+		want:      http.StatusBadRequest,
+	}, {
+		name:      "malformed payload",
+		body:      `{"status":"completed"}`,
+		signature: signWebhook(`{"status":"completed"}`, "secret"),
+		topic:     "bulk_operations/finish",
+		want:      http.StatusBadRequest,
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, deliverBulkOperationsFinish(handler, tt.body, tt.signature, tt.topic))
+			assert.Empty(t, finished)
+		})
+	}
+
+	assert.Equal(t, http.StatusOK, deliverBulkOperationsFinish(handler, bulkOperationsFinishPayload, signWebhook(bulkOperationsFinishPayload, "secret"), "bulk_operations/finish"))
+	assert.Len(t, finished, 1)
+	<-finished
+
+	// Without a secret the deliveries can't be verified, so none is accepted.
+	unverified := s.BulkOperationsFinishHandler("")
+	assert.Equal(t, http.StatusInternalServerError, deliverBulkOperationsFinish(unverified, bulkOperationsFinishPayload, "", "bulk_operations/finish"))
+	assert.Empty(t, finished)
+}
+
+func TestWaitForBulkOperationOnWebhook(t *testing.T) {
+	id := "gid://shopify/BulkOperation/1"
+	running := model.BulkOperation{ID: id, Status: model.BulkOperationStatusRunning}
+	completed := model.BulkOperation{ID: id, Status: model.BulkOperationStatusCompleted}
+
+	t.Run("resumed by the delivery", func(t *testing.T) {
+		gql := &bulkOperationGraphQL{op: running, upcoming: []model.BulkOperation{running, completed}}
+		s := NewClient("test", WithGraphQLClient(gql)).BulkOperation.(*BulkOperationServiceOp)
+		s.finish.enable()
+		handler := s.BulkOperationsFinishHandler("secret")
+
+		go func() {
+			// Deliver once the caller is waiting on the operation.
+			assert.Eventually(t, func() bool {
+				s.finish.mu.Lock()
+				defer s.finish.mu.Unlock()
+				return len(s.finish.waiters[id]) > 0
+			}, time.Second, time.Millisecond)
+			deliverBulkOperationsFinish(handler, bulkOperationsFinishPayload, signWebhook(bulkOperationsFinishPayload, "secret"), "bulk_operations/finish")
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		op, err := s.WaitForBulkOperation(ctx, id, 0, WithWebhookFallback(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, model.BulkOperationStatusCompleted, op.Status)
+		assert.Empty(t, s.finish.waiters)
+	})
+
+	t.Run("polled when the delivery is lost", func(t *testing.T) {
+		gql := &bulkOperationGraphQL{op: running, upcoming: []model.BulkOperation{running, running, completed}}
+		s := NewClient("test", WithGraphQLClient(gql)).BulkOperation.(*BulkOperationServiceOp)
+		s.finish.enable()
+
+		op, err := s.WaitForBulkOperation(context.Background(), id, 0, WithWebhookFallback(time.Millisecond))
+		require.NoError(t, err)
+		assert.Equal(t, model.BulkOperationStatusCompleted, op.Status)
+	})
+
+	t.Run("polled every interval set by the caller", func(t *testing.T) {
+		gql := &bulkOperationGraphQL{op: running, upcoming: []model.BulkOperation{running, running, completed}}
+		s := NewClient("test", WithGraphQLClient(gql)).BulkOperation.(*BulkOperationServiceOp)
+		s.finish.enable()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		op, err := s.WaitForBulkOperation(ctx, id, time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, model.BulkOperationStatusCompleted, op.Status)
+	})
+}
+
+// recordingWebhookService holds the subscriptions created through it.
+type recordingWebhookService struct {
+	WebhookService
+	subscriptions []*model.WebhookSubscription
+}
+
+func (s *recordingWebhookService) ListWebhookSubscriptions(ctx context.Context, topics []model.WebhookSubscriptionTopic) ([]*model.WebhookSubscription, error) {
+	return s.subscriptions, nil
+}
+
+func (s *recordingWebhookService) CreateWebhookSubscription(ctx context.Context, topic model.WebhookSubscriptionTopic, input model.WebhookSubscriptionInput) (*model.WebhookSubscription, error) {
+	sub := &model.WebhookSubscription{ID: "gid://shopify/WebhookSubscription/1", Topic: topic, CallbackURL: *input.CallbackURL}
+	s.subscriptions = append(s.subscriptions, sub)
+	return sub, nil
+}
+
+func TestSubscribeBulkOperationsFinish(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient("test")
+	webhooks := &recordingWebhookService{}
+	client.Webhook = webhooks
+	s := client.BulkOperation.(*BulkOperationServiceOp)
+
+	finished, stop := s.finish.wait("gid://shopify/BulkOperation/1")
+	stop()
+	assert.Nil(t, finished)
+
+	sub, err := s.SubscribeBulkOperationsFinish(ctx, "https://example.com/webhooks/bulk")
+	require.NoError(t, err)
+	assert.Equal(t, model.WebhookSubscriptionTopicBulkOperationsFinish, sub.Topic)
+
+	again, err := s.SubscribeBulkOperationsFinish(ctx, "https://example.com/webhooks/bulk")
+	require.NoError(t, err)
+	assert.Same(t, sub, again)
+	assert.Len(t, webhooks.subscriptions, 1)
+
+	finished, stop = s.finish.wait("gid://shopify/BulkOperation/1")
+	defer stop()
+	assert.NotNil(t, finished)
+}
@@ -24,6 +24,7 @@ const (
 
 type Client struct {
 	gql         graphql.GraphQL
+	shopName    string
 	accessToken string
 	apiKey      string
 	apiBasePath string
@@ -31,6 +32,9 @@ type Client struct {
 	timeout     time.Duration
 	transport   http.RoundTripper
 
+	bulkCheckpoint BulkCheckpointStore
+	bulkQueryLock  BulkQueryLock
+
 	Product       ProductService
 	Variant       VariantService
 	Inventory     InventoryService
@@ -41,10 +45,12 @@ type Client struct {
 	Metafield     MetafieldService
 	BulkOperation BulkOperationService
 	Webhook       WebhookService
+	Publication   PublicationService
 }
 
 func NewClient(shopName string, opts ...Option) *Client {
 	c := &Client{
+		shopName:    shopName,
 		apiBasePath: defaultAPIBasePath,
 		timeout:     defaultHttpTimeout,
 		transport:   http.DefaultTransport,
@@ -78,6 +84,7 @@ func NewClient(shopName string, opts ...Option) *Client {
 	c.Metafield = &MetafieldServiceOp{client: c}
 	c.BulkOperation = &BulkOperationServiceOp{client: c}
 	c.Webhook = &WebhookServiceOp{client: c}
+	c.Publication = &PublicationServiceOp{client: c}
 
 	return c
 }
@@ -106,88 +113,62 @@ func (c *Client) GraphQLClient() graphql.GraphQL {
 }
 
 func (c *Client) Mutate(ctx context.Context, m interface{}, variables map[string]interface{}) error {
-	var retries = 0
-	for {
-		r, err := c.gql.Mutate(ctx, m, variables)
-		if err != nil {
-			if r != nil {
-				wait := CalculateWaitTime(r.Extensions)
-				if wait > 0 {
-					retries++
-					time.Sleep(wait)
-					continue
-				}
-			}
-			if IsConnectionError(err) {
-				retries++
-				if retries > c.retries {
-					return fmt.Errorf("after %v tries: %w", retries, err)
-				}
-				time.Sleep(time.Duration(retries) * time.Second)
-				continue
-			}
-			return err
-		}
-		break
-	}
+	return c.retry(func() (*graphql.Result, error) {
+		return c.gql.Mutate(ctx, m, variables)
+	}, IsConnectionError)
+}
 
-	return nil
+func (c *Client) MutateString(ctx context.Context, m string, variables map[string]interface{}, out interface{}) error {
+	return c.retry(func() (*graphql.Result, error) {
+		return c.gql.MutateString(ctx, m, variables, out)
+	}, IsConnectionError)
 }
 
 func (c *Client) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
-	var retries = 0
-	for {
-		r, err := c.gql.Query(ctx, q, variables)
-		if err != nil {
-			if r != nil {
-				wait := CalculateWaitTime(r.Extensions)
-				if wait > 0 {
-					retries++
-					time.Sleep(wait)
-					continue
-				}
-			}
-			if uerr, isURLErr := err.(*url.Error); isURLErr && (uerr.Timeout() || uerr.Temporary()) || IsConnectionError(err) {
-				retries++
-				if retries > c.retries {
-					return fmt.Errorf("after %v tries: %w", retries, err)
-				}
-				time.Sleep(time.Duration(retries) * time.Second)
-				continue
-			}
-			return err
-		}
-		break
-	}
-
-	return nil
+	return c.retry(func() (*graphql.Result, error) {
+		return c.gql.Query(ctx, q, variables)
+	}, isRetryableQueryError)
 }
 
 func (c *Client) QueryString(ctx context.Context, q string, variables map[string]interface{}, out interface{}) error {
+	return c.retry(func() (*graphql.Result, error) {
+		return c.gql.QueryString(ctx, q, variables, out)
+	}, isRetryableQueryError)
+}
+
+// retry calls call until it succeeds. It waits out the throttling reported in the result extensions, and retries
+// the errors for which retryable is true up to the retries of the client, waiting a second longer each time.
+func (c *Client) retry(call func() (*graphql.Result, error), retryable func(error) bool) error {
 	var retries = 0
 	for {
-		r, err := c.gql.QueryString(ctx, q, variables, out)
-		if err != nil {
-			if r != nil {
-				wait := CalculateWaitTime(r.Extensions)
-				if wait > 0 {
-					retries++
-					time.Sleep(wait)
-					continue
-				}
-			}
-			if uerr, isURLErr := err.(*url.Error); isURLErr && (uerr.Timeout() || uerr.Temporary()) || IsConnectionError(err) {
+		r, err := call()
+		if err == nil {
+			return nil
+		}
+		if r != nil {
+			wait := CalculateWaitTime(r.Extensions)
+			if wait > 0 {
 				retries++
-				if retries > c.retries {
-					return fmt.Errorf("after %v tries: %w", retries, err)
-				}
-				time.Sleep(time.Duration(retries) * time.Second)
+				time.Sleep(wait)
 				continue
 			}
+		}
+		if !retryable(err) {
 			return err
 		}
-		break
+		retries++
+		if retries > c.retries {
+			return fmt.Errorf("after %v tries: %w", retries, err)
+		}
+		time.Sleep(time.Duration(retries) * time.Second)
 	}
+}
 
-	return nil
+// isRetryableQueryError reports whether a query can be sent again after err. Unlike mutations, queries are also
+// retried after a timeout, as they have no effect.
+func isRetryableQueryError(err error) bool {
+	if uerr, isURLErr := err.(*url.Error); isURLErr && (uerr.Timeout() || uerr.Temporary()) {
+		return true
+	}
+	return IsConnectionError(err)
 }
@@ -3,6 +3,7 @@ package shopify
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/sogko/go-shopify-graphql/model"
@@ -12,7 +13,7 @@ import (
 type CollectionService interface {
 	ListAll(ctx context.Context) ([]*model.Collection, error)
 
-	Get(ctx context.Context, id string) (*model.Collection, error)
+	Get(ctx context.Context, id string, opts ...QueryOption) (*model.Collection, error)
 
 	Create(ctx context.Context, collection model.CollectionInput) (*string, error)
 	CreateBulk(ctx context.Context, collections []model.CollectionInput) error
@@ -26,6 +27,8 @@ type CollectionServiceOp struct {
 
 var _ CollectionService = &CollectionServiceOp{}
 
+var collectionType = reflect.TypeOf(model.Collection{})
+
 type mutationCollectionCreate struct {
 	CollectionCreateResult struct {
 		Collection *struct {
@@ -88,7 +91,15 @@ func (s *CollectionServiceOp) ListAll(ctx context.Context) ([]*model.Collection,
 	return res, nil
 }
 
-func (s *CollectionServiceOp) Get(ctx context.Context, id string) (*model.Collection, error) {
+func (s *CollectionServiceOp) Get(ctx context.Context, id string, opts ...QueryOption) (*model.Collection, error) {
+	fields, err := selectFields(collectionType, newQueryOptions(opts).fields, "")
+	if err != nil {
+		return nil, err
+	}
+	if fields != "" {
+		return s.getFields(ctx, id, fields)
+	}
+
 	out, err := s.getPage(ctx, id, "")
 	if err != nil {
 		return nil, err
@@ -136,6 +147,31 @@ func (s *CollectionServiceOp) getPage(ctx context.Context, id string, cursor str
 	return out.Collection, nil
 }
 
+// getFields returns the collection with the selected fields, as they're selected.
+func (s *CollectionServiceOp) getFields(ctx context.Context, id string, fields string) (*model.Collection, error) {
+	q := fmt.Sprintf(`
+		query collection($id: ID!) {
+			collection(id: $id){
+				%s
+			}
+		}
+	`, fields)
+
+	vars := map[string]interface{}{
+		"id": id,
+	}
+
+	out := struct {
+		Collection *model.Collection `json:"collection"`
+	}{}
+	err := s.client.QueryString(ctx, q, vars, &out)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return out.Collection, nil
+}
+
 func (s *CollectionServiceOp) CreateBulk(ctx context.Context, collections []model.CollectionInput) error {
 	for _, c := range collections {
 		_, err := s.client.Collection.Create(ctx, c)
@@ -1,9 +1,46 @@
 package shopify
 
 import (
+	"errors"
+	"fmt"
 	"strings"
+
+	"github.com/sogko/go-shopify-graphql/model"
 )
 
+// ErrBulkQueryInProgress is returned when a bulk query is posted while another one is running for the shop.
+var ErrBulkQueryInProgress = errors.New("a bulk query is already in progress")
+
+// ErrBulkOperationNotOwned is returned when cancelling a bulk operation that another job started, see WithForceCancel.
+var ErrBulkOperationNotOwned = errors.New("the bulk operation was started by another job")
+
+// ErrAmbiguousVariant is returned when a SKU or barcode looked up matches several variants.
+var ErrAmbiguousVariant = errors.New("several variants match")
+
 func IsConnectionError(err error) bool {
 	return err != nil && (strings.Contains(err.Error(), "connection reset by peer") || strings.Contains(err.Error(), "broken pipe"))
 }
+
+// BulkOperationError is returned when a bulk operation doesn't complete, e.g. it failed with a TIMEOUT or was
+// canceled.
+type BulkOperationError struct {
+	ID        string
+	Status    model.BulkOperationStatus
+	ErrorCode *model.BulkOperationErrorCode
+	// PartialData is set when the partial data of the operation was read, see WithPartialData.
+	PartialData bool
+	// Recovered is the count of root objects read from the partial data.
+	Recovered int
+}
+
+func (e *BulkOperationError) Error() string {
+	var errorCode model.BulkOperationErrorCode
+	if e.ErrorCode != nil {
+		errorCode = *e.ErrorCode
+	}
+	msg := fmt.Sprintf("Bulk operation didn't complete, status=%s, error_code=%s", e.Status, errorCode)
+	if e.PartialData {
+		msg += fmt.Sprintf(", recovered %d objects from the partial data", e.Recovered)
+	}
+	return msg
+}
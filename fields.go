@@ -0,0 +1,114 @@
+package shopify
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/sogko/go-shopify-graphql/schema"
+)
+
+// QueryOption optionally changes the fields selected by the Get and List methods.
+type QueryOption func(*queryOptions)
+
+type queryOptions struct {
+	fields string
+}
+
+func newQueryOptions(opts []QueryOption) *queryOptions {
+	options := &queryOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithFields selects the fields of the object instead of the default ones, e.g.
+// `id status variants(first: 10){ edges{ node{ id sku } } }`. The fields must exist on the model type the object
+// is decoded into, and the connections are fetched as selected, without paging through them.
+func WithFields(fields string) QueryOption {
+	return func(o *queryOptions) {
+		o.fields = fields
+	}
+}
+
+// selectFields returns the fields validated against the model type t, or def when no fields are selected.
+func selectFields(t reflect.Type, fields, def string) (string, error) {
+	if fields == "" {
+		return def, nil
+	}
+
+	set, err := schema.ParseSelectionSet(fields)
+	if err != nil {
+		return "", fmt.Errorf("parse fields: %w", err)
+	}
+	if len(set) == 0 {
+		return def, nil
+	}
+	err = validateSelectionSet(t, set, t.Name())
+	if err != nil {
+		return "", fmt.Errorf("invalid fields: %w", err)
+	}
+	// The fields are written within the braces of the object, so those of a braced selection set are left out.
+	if trimmed := strings.TrimSpace(fields); strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}") {
+		fields = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+	}
+	return fields, nil
+}
+
+// validateSelectionSet checks that the fields of the selection set exist on the struct t, so they're decoded.
+func validateSelectionSet(t reflect.Type, set schema.SelectionSet, path string) error {
+	fields := graphQLFields(t)
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *schema.Field:
+			if sel.Name == "__typename" {
+				continue
+			}
+			if sel.Alias != "" && sel.Alias != sel.Name {
+				return fmt.Errorf("%s: the alias `%s` of `%s` isn't decoded into %s", path, sel.Alias, sel.Name, t)
+			}
+			f, ok := fields[sel.Name]
+			if !ok {
+				return fmt.Errorf("%s: %s has no field `%s`", path, t, sel.Name)
+			}
+			err := validateFieldSelection(f.typ, sel.SelectionSet, path+"."+sel.Name)
+			if err != nil {
+				return err
+			}
+		case *schema.InlineFragment:
+			err := validateSelectionSet(t, sel.SelectionSet, path)
+			if err != nil {
+				return err
+			}
+		case *schema.FragmentSpread:
+			return fmt.Errorf("%s: the fragment `%s` can't be spread, use an inline fragment", path, sel.Name)
+		}
+	}
+	return nil
+}
+
+func validateFieldSelection(t reflect.Type, set schema.SelectionSet, path string) error {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if isGraphQLScalar(t) {
+		if len(set) > 0 {
+			return fmt.Errorf("%s: %s has no fields to select", path, t)
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if len(set) == 0 {
+			return fmt.Errorf("%s: the fields of %s must be selected", path, t)
+		}
+		return validateSelectionSet(t, set, path)
+	case reflect.Interface:
+		// The fields of the types implementing it are only known to the schema.
+		return nil
+	default:
+		return fmt.Errorf("%s: %s can't be queried", path, t)
+	}
+}
@@ -0,0 +1,76 @@
+package shopify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectFields(t *testing.T) {
+	fields, err := selectFields(productType, "", productBaseQuery)
+	require.NoError(t, err)
+	assert.Equal(t, productBaseQuery, fields)
+
+	tests := []struct {
+		name    string
+		fields  string
+		want    string
+		wantErr string
+	}{{
+		name:   "scalars and connections",
+		fields: `id status publishedAt variants(first: 10){ edges{ node{ id sku } } } metafields(first: 5){ edges{ node{ key value } } }`,
+	}, {
+		name:   "braced",
+		fields: ` { id title variants(first: 10){ edges{ node{ id } } } } `,
+		want:   `id title variants(first: 10){ edges{ node{ id } } }`,
+	}, {
+		name:   "inline fragment",
+		fields: `{ __typename ... on Product { id handle } }`,
+		want:   `__typename ... on Product { id handle }`,
+	}, {
+		name:    "unknown field",
+		fields:  `id colour`,
+		wantErr: "invalid fields: Product: model.Product has no field `colour`",
+	}, {
+		name:    "unknown nested field",
+		fields:  `variants(first: 10){ edges{ node{ sku colour } } }`,
+		wantErr: "invalid fields: Product.variants.edges.node: model.ProductVariant has no field `colour`",
+	}, {
+		name:    "fields of a scalar",
+		fields:  `title{ value }`,
+		wantErr: "invalid fields: Product.title: string has no fields to select",
+	}, {
+		name:    "object without fields",
+		fields:  `seo`,
+		wantErr: "invalid fields: Product.seo: the fields of model.Seo must be selected",
+	}, {
+		name:    "alias",
+		fields:  `name: title`,
+		wantErr: "invalid fields: Product: the alias `name` of `title` isn't decoded into model.Product",
+	}, {
+		name:    "fragment spread",
+		fields:  `...productFields`,
+		wantErr: "invalid fields: Product: the fragment `productFields` can't be spread, use an inline fragment",
+	}, {
+		name:    "syntax error",
+		fields:  `id {`,
+		wantErr: "parse fields: 1:4: unclosed selection set",
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := selectFields(productType, tt.fields, productBaseQuery)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			want := tt.fields
+			if tt.want != "" {
+				want = tt.want
+			}
+			assert.Equal(t, want, fields)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package shopify
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/sogko/go-shopify-graphql/schema"
+	"github.com/vinhluan/go-graphql-client"
+)
+
+// queuedOperation is an operation sent to the queuedGraphQL, either as a document or as the struct v.
+type queuedOperation struct {
+	op        schema.OperationType
+	field     string
+	document  string
+	v         interface{}
+	variables map[string]interface{}
+}
+
+// queuedGraphQL records the operations it's sent and answers each with the next of the responses queued for its root
+// field, e.g. `"productUpdate": {`{"product":{"id":"gid://shopify/Product/1"}}`}`. The last response of a field
+// answers every later operation on it, and an operation without response is left empty. When err is set, every
+// operation fails with it.
+type queuedGraphQL struct {
+	graphql.GraphQL
+	responses  map[string][]string
+	err        error
+	operations []queuedOperation
+}
+
+func (g *queuedGraphQL) QueryString(ctx context.Context, q string, variables map[string]interface{}, v interface{}) (*graphql.Result, error) {
+	return g.answer(schema.OperationQuery, q, variables, v)
+}
+
+func (g *queuedGraphQL) Query(ctx context.Context, q interface{}, variables map[string]interface{}) (*graphql.Result, error) {
+	return g.answer(schema.OperationQuery, q, variables, q)
+}
+
+func (g *queuedGraphQL) MutateString(ctx context.Context, m string, variables map[string]interface{}, v interface{}) (*graphql.Result, error) {
+	return g.answer(schema.OperationMutation, m, variables, v)
+}
+
+func (g *queuedGraphQL) Mutate(ctx context.Context, m interface{}, variables map[string]interface{}) (*graphql.Result, error) {
+	return g.answer(schema.OperationMutation, m, variables, m)
+}
+
+// answer records the operation, a document or a struct, and decodes the next response of its root field into v.
+func (g *queuedGraphQL) answer(op schema.OperationType, operation interface{}, variables map[string]interface{}, v interface{}) (*graphql.Result, error) {
+	recorded := queuedOperation{op: op, variables: map[string]interface{}{}}
+	for k, val := range variables {
+		recorded.variables[k] = val
+	}
+	var err error
+	if document, ok := operation.(string); ok {
+		recorded.document = document
+		var doc *schema.QueryDocument
+		if doc, err = schema.ParseQuery(document); err == nil {
+			recorded.field = doc.Operations[0].SelectionSet[0].(*schema.Field).Name
+		}
+	} else {
+		recorded.v = operation
+		recorded.field = structRootField(operation)
+	}
+	g.operations = append(g.operations, recorded)
+
+	if err != nil {
+		return nil, err
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+	responses := g.responses[recorded.field]
+	if len(responses) == 0 {
+		return &graphql.Result{}, nil
+	}
+	if len(responses) > 1 {
+		g.responses[recorded.field] = responses[1:]
+	}
+	return &graphql.Result{}, jsoniter.UnmarshalFromString(fmt.Sprintf(`{"%s":%s}`, recorded.field, responses[0]), v)
+}
+
+// mutations returns the root fields of the mutations sent, in order.
+func (g *queuedGraphQL) mutations() []string {
+	var fields []string
+	for _, op := range g.operations {
+		if op.op == schema.OperationMutation {
+			fields = append(fields, op.field)
+		}
+	}
+	return fields
+}
+
+// variables returns the variables of the operations sent on the root field, in order.
+func (g *queuedGraphQL) variables(field string) []map[string]interface{} {
+	var variables []map[string]interface{}
+	for _, op := range g.operations {
+		if op.field == field {
+			variables = append(variables, op.variables)
+		}
+	}
+	return variables
+}
+
+// structRootField returns the name of the root field of the operation struct v, from the `graphql` tag, the `json` tag
+// or the name of its first field.
+func structRootField(v interface{}) string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	f := t.Field(0)
+	if tag := f.Tag.Get("graphql"); tag != "" {
+		return strings.TrimSpace(strings.SplitN(tag, "(", 2)[0])
+	}
+	if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag != "" {
+		return tag
+	}
+	return strings.ToLower(f.Name[:1]) + f.Name[1:]
+}
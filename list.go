@@ -1,10 +1,74 @@
 package shopify
 
+import (
+	"fmt"
+
+	"github.com/sogko/go-shopify-graphql/model"
+)
+
+// maxListPageSize is the most nodes a connection returns per page.
+const maxListPageSize = 250
+
+// defaultListPageSize is the page size when neither First nor Last is set.
+const defaultListPageSize = 50
+
 type ListOptions struct {
+	// Query filters the objects in the Shopify search syntax, see SearchQuery.
 	Query   string
 	First   int
 	Last    int
 	After   string
 	Before  string
 	Reverse bool
+	// SortKey sorts the objects by a sort key of the connection, e.g. `model.ProductSortKeysTitle.String()`.
+	SortKey string
+	// Fields selects the fields of the listed objects instead of the default ones, e.g. `id title`.
+	Fields string
+}
+
+// listPageVariables returns the variables of a connection query paginated by the options, fetching the first
+// defaultListPageSize objects unless First or Last is set.
+func listPageVariables(opts ListOptions) (map[string]interface{}, error) {
+	if opts.First < 0 || opts.Last < 0 || opts.First > maxListPageSize || opts.Last > maxListPageSize {
+		return nil, fmt.Errorf("the page size must be between 1 and %d", maxListPageSize)
+	}
+	if opts.First > 0 && opts.Last > 0 {
+		return nil, fmt.Errorf("only one of First and Last can be set")
+	}
+	if opts.After != "" && opts.Before != "" {
+		return nil, fmt.Errorf("only one of After and Before can be set")
+	}
+
+	vars := map[string]interface{}{
+		"reverse": opts.Reverse,
+	}
+	if opts.Query != "" {
+		vars["query"] = opts.Query
+	}
+	if opts.After != "" {
+		vars["after"] = opts.After
+	} else if opts.Before != "" {
+		vars["before"] = opts.Before
+	}
+
+	switch {
+	case opts.First > 0:
+		vars["first"] = opts.First
+	case opts.Last > 0:
+		vars["last"] = opts.Last
+	case opts.Before != "":
+		// Paging backwards reads the objects before the cursor.
+		vars["last"] = defaultListPageSize
+	default:
+		vars["first"] = defaultListPageSize
+	}
+	return vars, nil
+}
+
+// nextPageCursor returns the cursor of the page after the one described by the page info, if there's one.
+func nextPageCursor(info *model.PageInfo) (string, bool) {
+	if info == nil || !info.HasNextPage || info.EndCursor == nil {
+		return "", false
+	}
+	return *info.EndCursor, true
 }
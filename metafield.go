@@ -100,22 +100,40 @@ func (s *MetafieldServiceOp) ListShopMetafieldsByNamespace(ctx context.Context,
 }
 
 func (s *MetafieldServiceOp) GetShopMetafieldByKey(ctx context.Context, namespace, key string) (*model.Metafield, error) {
-	var q struct {
-		Shop struct {
-			Metafield model.Metafield `graphql:"metafield(namespace: $namespace, key: $key)"`
-		} `graphql:"shop"`
-	}
+	q := `
+		query shopMetafield($namespace: String!, $key: String!) {
+			shop{
+				metafield(namespace: $namespace, key: $key){
+					createdAt
+					description
+					id
+					key
+					legacyResourceId
+					namespace
+					ownerType
+					updatedAt
+					value
+					type
+				}
+			}
+		}
+`
 	vars := map[string]interface{}{
 		"namespace": namespace,
 		"key":       key,
 	}
 
-	err := s.client.Query(ctx, &q, vars)
+	var out struct {
+		Shop struct {
+			Metafield model.Metafield `json:"metafield"`
+		} `json:"shop"`
+	}
+	err := s.client.QueryString(ctx, q, vars, &out)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
 
-	return &q.Shop.Metafield, nil
+	return &out.Shop.Metafield, nil
 }
 
 func (s *MetafieldServiceOp) DeleteBulk(ctx context.Context, metafields []model.MetafieldDeleteInput) error {
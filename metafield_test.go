@@ -0,0 +1,22 @@
+package shopify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetShopMetafieldByKey(t *testing.T) {
+	gql := &queuedGraphQL{responses: map[string][]string{"shop": {
+		`{"metafield":{"id":"gid://shopify/Metafield/1","namespace":"custom","key":"banner","value":"Sale","type":"single_line_text_field"}}`,
+	}}}
+	client := NewClient("test", WithGraphQLClient(gql))
+
+	metafield, err := client.Metafield.GetShopMetafieldByKey(context.Background(), "custom", "banner")
+	require.NoError(t, err)
+	assert.Equal(t, "gid://shopify/Metafield/1", metafield.ID)
+	assert.Equal(t, "Sale", metafield.Value)
+	assert.Equal(t, map[string]interface{}{"namespace": "custom", "key": "banner"}, gql.variables("shop")[0])
+}
@@ -6,10 +6,13 @@ package mock
 
 import (
 	context "context"
+	io "io"
+	http "net/http"
 	reflect "reflect"
 	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	shopify "github.com/sogko/go-shopify-graphql"
 	model "github.com/sogko/go-shopify-graphql/model"
 )
 
@@ -36,18 +39,91 @@ func (m *MockBulkOperationService) EXPECT() *MockBulkOperationServiceMockRecorde
 	return m.recorder
 }
 
+// BulkMutation mocks base method.
+func (m *MockBulkOperationService) BulkMutation(arg0 context.Context, arg1 string, arg2, arg3 interface{}) ([]shopify.BulkMutationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkMutation", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]shopify.BulkMutationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkMutation indicates an expected call of BulkMutation.
+func (mr *MockBulkOperationServiceMockRecorder) BulkMutation(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkMutation", reflect.TypeOf((*MockBulkOperationService)(nil).BulkMutation), arg0, arg1, arg2, arg3)
+}
+
+// BulkOperationsFinishHandler mocks base method.
+func (m *MockBulkOperationService) BulkOperationsFinishHandler(arg0 string) http.Handler {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkOperationsFinishHandler", arg0)
+	ret0, _ := ret[0].(http.Handler)
+	return ret0
+}
+
+// BulkOperationsFinishHandler indicates an expected call of BulkOperationsFinishHandler.
+func (mr *MockBulkOperationServiceMockRecorder) BulkOperationsFinishHandler(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkOperationsFinishHandler", reflect.TypeOf((*MockBulkOperationService)(nil).BulkOperationsFinishHandler), arg0)
+}
+
 // BulkQuery mocks base method.
-func (m *MockBulkOperationService) BulkQuery(arg0 context.Context, arg1 string, arg2 interface{}) error {
+func (m *MockBulkOperationService) BulkQuery(arg0 context.Context, arg1 string, arg2 interface{}, arg3 ...shopify.BulkQueryOption) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BulkQuery", arg0, arg1, arg2)
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BulkQuery", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // BulkQuery indicates an expected call of BulkQuery.
-func (mr *MockBulkOperationServiceMockRecorder) BulkQuery(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockBulkOperationServiceMockRecorder) BulkQuery(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkQuery", reflect.TypeOf((*MockBulkOperationService)(nil).BulkQuery), varargs...)
+}
+
+// BulkQueryEach mocks base method.
+func (m *MockBulkOperationService) BulkQueryEach(arg0 context.Context, arg1 string, arg2 interface{}, arg3 ...shopify.BulkQueryOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BulkQueryEach", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkQueryEach indicates an expected call of BulkQueryEach.
+func (mr *MockBulkOperationServiceMockRecorder) BulkQueryEach(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkQuery", reflect.TypeOf((*MockBulkOperationService)(nil).BulkQuery), arg0, arg1, arg2)
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkQueryEach", reflect.TypeOf((*MockBulkOperationService)(nil).BulkQueryEach), varargs...)
+}
+
+// CancelBulkOperation mocks base method.
+func (m *MockBulkOperationService) CancelBulkOperation(arg0 context.Context, arg1 string, arg2 time.Duration, arg3 ...shopify.BulkQueryOption) (*model.BulkOperation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CancelBulkOperation", varargs...)
+	ret0, _ := ret[0].(*model.BulkOperation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelBulkOperation indicates an expected call of CancelBulkOperation.
+func (mr *MockBulkOperationServiceMockRecorder) CancelBulkOperation(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelBulkOperation", reflect.TypeOf((*MockBulkOperationService)(nil).CancelBulkOperation), varargs...)
 }
 
 // CancelRunningBulkQuery mocks base method.
@@ -64,6 +140,40 @@ func (mr *MockBulkOperationServiceMockRecorder) CancelRunningBulkQuery(arg0 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelRunningBulkQuery", reflect.TypeOf((*MockBulkOperationService)(nil).CancelRunningBulkQuery), arg0)
 }
 
+// DownloadBulkQueryResult mocks base method.
+func (m *MockBulkOperationService) DownloadBulkQueryResult(arg0 context.Context, arg1 string, arg2 io.Writer, arg3 ...shopify.BulkQueryOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DownloadBulkQueryResult", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadBulkQueryResult indicates an expected call of DownloadBulkQueryResult.
+func (mr *MockBulkOperationServiceMockRecorder) DownloadBulkQueryResult(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadBulkQueryResult", reflect.TypeOf((*MockBulkOperationService)(nil).DownloadBulkQueryResult), varargs...)
+}
+
+// GetBulkOperation mocks base method.
+func (m *MockBulkOperationService) GetBulkOperation(arg0 context.Context, arg1 string) (*model.BulkOperation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBulkOperation", arg0, arg1)
+	ret0, _ := ret[0].(*model.BulkOperation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBulkOperation indicates an expected call of GetBulkOperation.
+func (mr *MockBulkOperationServiceMockRecorder) GetBulkOperation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBulkOperation", reflect.TypeOf((*MockBulkOperationService)(nil).GetBulkOperation), arg0, arg1)
+}
+
 // GetCurrentBulkQuery mocks base method.
 func (m *MockBulkOperationService) GetCurrentBulkQuery(arg0 context.Context) (*model.BulkOperation, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +204,21 @@ func (mr *MockBulkOperationServiceMockRecorder) GetCurrentBulkQueryResultURL(arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentBulkQueryResultURL", reflect.TypeOf((*MockBulkOperationService)(nil).GetCurrentBulkQueryResultURL), arg0)
 }
 
+// PostBulkMutation mocks base method.
+func (m *MockBulkOperationService) PostBulkMutation(arg0 context.Context, arg1, arg2 string) (*string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostBulkMutation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostBulkMutation indicates an expected call of PostBulkMutation.
+func (mr *MockBulkOperationServiceMockRecorder) PostBulkMutation(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostBulkMutation", reflect.TypeOf((*MockBulkOperationService)(nil).PostBulkMutation), arg0, arg1, arg2)
+}
+
 // PostBulkQuery mocks base method.
 func (m *MockBulkOperationService) PostBulkQuery(arg0 context.Context, arg1 string) (*string, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +234,44 @@ func (mr *MockBulkOperationServiceMockRecorder) PostBulkQuery(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostBulkQuery", reflect.TypeOf((*MockBulkOperationService)(nil).PostBulkQuery), arg0, arg1)
 }
 
+// ResumeBulkQuery mocks base method.
+func (m *MockBulkOperationService) ResumeBulkQuery(arg0 context.Context, arg1 string, arg2 interface{}, arg3 ...shopify.BulkQueryOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResumeBulkQuery", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeBulkQuery indicates an expected call of ResumeBulkQuery.
+func (mr *MockBulkOperationServiceMockRecorder) ResumeBulkQuery(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeBulkQuery", reflect.TypeOf((*MockBulkOperationService)(nil).ResumeBulkQuery), varargs...)
+}
+
+// ResumeBulkQueryEach mocks base method.
+func (m *MockBulkOperationService) ResumeBulkQueryEach(arg0 context.Context, arg1 string, arg2 interface{}, arg3 ...shopify.BulkQueryOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResumeBulkQueryEach", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeBulkQueryEach indicates an expected call of ResumeBulkQueryEach.
+func (mr *MockBulkOperationServiceMockRecorder) ResumeBulkQueryEach(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeBulkQueryEach", reflect.TypeOf((*MockBulkOperationService)(nil).ResumeBulkQueryEach), varargs...)
+}
+
 // ShouldGetBulkQueryResultURL mocks base method.
 func (m *MockBulkOperationService) ShouldGetBulkQueryResultURL(arg0 context.Context, arg1 *string) (*string, error) {
 	m.ctrl.T.Helper()
@@ -124,17 +287,57 @@ func (mr *MockBulkOperationServiceMockRecorder) ShouldGetBulkQueryResultURL(arg0
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldGetBulkQueryResultURL", reflect.TypeOf((*MockBulkOperationService)(nil).ShouldGetBulkQueryResultURL), arg0, arg1)
 }
 
+// SubscribeBulkOperationsFinish mocks base method.
+func (m *MockBulkOperationService) SubscribeBulkOperationsFinish(arg0 context.Context, arg1 string) (*model.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeBulkOperationsFinish", arg0, arg1)
+	ret0, _ := ret[0].(*model.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeBulkOperationsFinish indicates an expected call of SubscribeBulkOperationsFinish.
+func (mr *MockBulkOperationServiceMockRecorder) SubscribeBulkOperationsFinish(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeBulkOperationsFinish", reflect.TypeOf((*MockBulkOperationService)(nil).SubscribeBulkOperationsFinish), arg0, arg1)
+}
+
+// WaitForBulkOperation mocks base method.
+func (m *MockBulkOperationService) WaitForBulkOperation(arg0 context.Context, arg1 string, arg2 time.Duration, arg3 ...shopify.BulkQueryOption) (*model.BulkOperation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WaitForBulkOperation", varargs...)
+	ret0, _ := ret[0].(*model.BulkOperation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForBulkOperation indicates an expected call of WaitForBulkOperation.
+func (mr *MockBulkOperationServiceMockRecorder) WaitForBulkOperation(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForBulkOperation", reflect.TypeOf((*MockBulkOperationService)(nil).WaitForBulkOperation), varargs...)
+}
+
 // WaitForCurrentBulkQuery mocks base method.
-func (m *MockBulkOperationService) WaitForCurrentBulkQuery(arg0 context.Context, arg1 time.Duration) (*model.BulkOperation, error) {
+func (m *MockBulkOperationService) WaitForCurrentBulkQuery(arg0 context.Context, arg1 time.Duration, arg2 ...shopify.BulkQueryOption) (*model.BulkOperation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WaitForCurrentBulkQuery", arg0, arg1)
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WaitForCurrentBulkQuery", varargs...)
 	ret0, _ := ret[0].(*model.BulkOperation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // WaitForCurrentBulkQuery indicates an expected call of WaitForCurrentBulkQuery.
-func (mr *MockBulkOperationServiceMockRecorder) WaitForCurrentBulkQuery(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockBulkOperationServiceMockRecorder) WaitForCurrentBulkQuery(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForCurrentBulkQuery", reflect.TypeOf((*MockBulkOperationService)(nil).WaitForCurrentBulkQuery), arg0, arg1)
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForCurrentBulkQuery", reflect.TypeOf((*MockBulkOperationService)(nil).WaitForCurrentBulkQuery), varargs...)
 }
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	shopify "github.com/sogko/go-shopify-graphql"
 	model "github.com/sogko/go-shopify-graphql/model"
 )
 
@@ -65,18 +66,23 @@ func (mr *MockCollectionServiceMockRecorder) CreateBulk(arg0, arg1 interface{})
 }
 
 // Get mocks base method.
-func (m *MockCollectionService) Get(arg0 context.Context, arg1 string) (*model.Collection, error) {
+func (m *MockCollectionService) Get(arg0 context.Context, arg1 string, arg2 ...shopify.QueryOption) (*model.Collection, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Get", varargs...)
 	ret0, _ := ret[0].(*model.Collection)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockCollectionServiceMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockCollectionServiceMockRecorder) Get(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCollectionService)(nil).Get), arg0, arg1)
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCollectionService)(nil).Get), varargs...)
 }
 
 // ListAll mocks base method.
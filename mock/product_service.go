@@ -7,8 +7,10 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	shopify "github.com/sogko/go-shopify-graphql"
 	model "github.com/sogko/go-shopify-graphql/model"
 )
 
@@ -50,6 +52,36 @@ func (mr *MockProductServiceMockRecorder) Create(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductService)(nil).Create), arg0, arg1, arg2)
 }
 
+// CreateMedia mocks base method.
+func (m *MockProductService) CreateMedia(arg0 context.Context, arg1 string, arg2 []model.CreateMediaInput) ([]*shopify.ProductMedia, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMedia", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*shopify.ProductMedia)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMedia indicates an expected call of CreateMedia.
+func (mr *MockProductServiceMockRecorder) CreateMedia(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMedia", reflect.TypeOf((*MockProductService)(nil).CreateMedia), arg0, arg1, arg2)
+}
+
+// CreateOption mocks base method.
+func (m *MockProductService) CreateOption(arg0 context.Context, arg1 string, arg2 string, arg3 string) (*model.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOption", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOption indicates an expected call of CreateOption.
+func (mr *MockProductServiceMockRecorder) CreateOption(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOption", reflect.TypeOf((*MockProductService)(nil).CreateOption), arg0, arg1, arg2, arg3)
+}
+
 // Delete mocks base method.
 func (m *MockProductService) Delete(arg0 context.Context, arg1 model.ProductDeleteInput) error {
 	m.ctrl.T.Helper()
@@ -64,34 +96,74 @@ func (mr *MockProductServiceMockRecorder) Delete(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductService)(nil).Delete), arg0, arg1)
 }
 
+// DeleteMedia mocks base method.
+func (m *MockProductService) DeleteMedia(arg0 context.Context, arg1 string, arg2 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMedia", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMedia indicates an expected call of DeleteMedia.
+func (mr *MockProductServiceMockRecorder) DeleteMedia(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMedia", reflect.TypeOf((*MockProductService)(nil).DeleteMedia), arg0, arg1, arg2)
+}
+
+// DeleteOption mocks base method.
+func (m *MockProductService) DeleteOption(arg0 context.Context, arg1 string, arg2 string) (*model.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOption", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOption indicates an expected call of DeleteOption.
+func (mr *MockProductServiceMockRecorder) DeleteOption(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOption", reflect.TypeOf((*MockProductService)(nil).DeleteOption), arg0, arg1, arg2)
+}
+
 // Get mocks base method.
-func (m *MockProductService) Get(arg0 context.Context, arg1 string) (*model.Product, error) {
+func (m *MockProductService) Get(arg0 context.Context, arg1 string, arg2 ...shopify.QueryOption) (*model.Product, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Get", varargs...)
 	ret0, _ := ret[0].(*model.Product)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockProductServiceMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockProductServiceMockRecorder) Get(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProductService)(nil).Get), arg0, arg1)
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProductService)(nil).Get), varargs...)
 }
 
 // List mocks base method.
-func (m *MockProductService) List(arg0 context.Context, arg1 string) ([]*model.Product, error) {
+func (m *MockProductService) List(arg0 context.Context, arg1 string, arg2 ...shopify.QueryOption) ([]*model.Product, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "List", varargs...)
 	ret0, _ := ret[0].([]*model.Product)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockProductServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockProductServiceMockRecorder) List(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockProductService)(nil).List), arg0, arg1)
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockProductService)(nil).List), varargs...)
 }
 
 // ListAll mocks base method.
@@ -109,6 +181,101 @@ func (mr *MockProductServiceMockRecorder) ListAll(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockProductService)(nil).ListAll), arg0)
 }
 
+// ListPage mocks base method.
+func (m *MockProductService) ListPage(arg0 context.Context, arg1 shopify.ListOptions) ([]*model.Product, *model.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPage", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Product)
+	ret1, _ := ret[1].(*model.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPage indicates an expected call of ListPage.
+func (mr *MockProductServiceMockRecorder) ListPage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPage", reflect.TypeOf((*MockProductService)(nil).ListPage), arg0, arg1)
+}
+
+// ReorderMedia mocks base method.
+func (m *MockProductService) ReorderMedia(arg0 context.Context, arg1 string, arg2 []model.MoveInput) (*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderMedia", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReorderMedia indicates an expected call of ReorderMedia.
+func (mr *MockProductServiceMockRecorder) ReorderMedia(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderMedia", reflect.TypeOf((*MockProductService)(nil).ReorderMedia), arg0, arg1, arg2)
+}
+
+// ReorderOptionValues mocks base method.
+func (m *MockProductService) ReorderOptionValues(arg0 context.Context, arg1 string, arg2 string, arg3 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderOptionValues", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderOptionValues indicates an expected call of ReorderOptionValues.
+func (mr *MockProductServiceMockRecorder) ReorderOptionValues(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderOptionValues", reflect.TypeOf((*MockProductService)(nil).ReorderOptionValues), arg0, arg1, arg2, arg3)
+}
+
+// ReorderOptions mocks base method.
+func (m *MockProductService) ReorderOptions(arg0 context.Context, arg1 string, arg2 []string) (*model.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderOptions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReorderOptions indicates an expected call of ReorderOptions.
+func (mr *MockProductServiceMockRecorder) ReorderOptions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderOptions", reflect.TypeOf((*MockProductService)(nil).ReorderOptions), arg0, arg1, arg2)
+}
+
+// Sync mocks base method.
+func (m *MockProductService) Sync(arg0 context.Context, arg1 []model.ProductInput, arg2 ...shopify.SyncOption) (*shopify.SyncReport, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Sync", varargs...)
+	ret0, _ := ret[0].(*shopify.SyncReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockProductServiceMockRecorder) Sync(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockProductService)(nil).Sync), varargs...)
+}
+
+// SyncVariantMatrix mocks base method.
+func (m *MockProductService) SyncVariantMatrix(arg0 context.Context, arg1 string, arg2 shopify.VariantMatrix) (*shopify.VariantMatrixResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncVariantMatrix", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*shopify.VariantMatrixResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncVariantMatrix indicates an expected call of SyncVariantMatrix.
+func (mr *MockProductServiceMockRecorder) SyncVariantMatrix(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncVariantMatrix", reflect.TypeOf((*MockProductService)(nil).SyncVariantMatrix), arg0, arg1, arg2)
+}
+
 // Update mocks base method.
 func (m *MockProductService) Update(arg0 context.Context, arg1 model.ProductInput) (*model.Product, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +291,71 @@ func (mr *MockProductServiceMockRecorder) Update(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductService)(nil).Update), arg0, arg1)
 }
 
+// UpdateMedia mocks base method.
+func (m *MockProductService) UpdateMedia(arg0 context.Context, arg1 string, arg2 []model.UpdateMediaInput) ([]*shopify.ProductMedia, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMedia", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*shopify.ProductMedia)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMedia indicates an expected call of UpdateMedia.
+func (mr *MockProductServiceMockRecorder) UpdateMedia(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMedia", reflect.TypeOf((*MockProductService)(nil).UpdateMedia), arg0, arg1, arg2)
+}
+
+// UpdateOption mocks base method.
+func (m *MockProductService) UpdateOption(arg0 context.Context, arg1 string, arg2 string, arg3 shopify.ProductOptionUpdate) (*model.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOption", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOption indicates an expected call of UpdateOption.
+func (mr *MockProductServiceMockRecorder) UpdateOption(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOption", reflect.TypeOf((*MockProductService)(nil).UpdateOption), arg0, arg1, arg2, arg3)
+}
+
+// UploadMedia mocks base method.
+func (m *MockProductService) UploadMedia(arg0 context.Context, arg1 string, arg2 ...shopify.MediaUpload) ([]*shopify.ProductMedia, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UploadMedia", varargs...)
+	ret0, _ := ret[0].([]*shopify.ProductMedia)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadMedia indicates an expected call of UploadMedia.
+func (mr *MockProductServiceMockRecorder) UploadMedia(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadMedia", reflect.TypeOf((*MockProductService)(nil).UploadMedia), varargs...)
+}
+
+// Upsert mocks base method.
+func (m *MockProductService) Upsert(arg0 context.Context, arg1 shopify.ProductUpsertKey, arg2 shopify.ProductUpsert) (*shopify.ProductUpsertResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*shopify.ProductUpsertResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockProductServiceMockRecorder) Upsert(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockProductService)(nil).Upsert), arg0, arg1, arg2)
+}
+
 // VariantsBulkCreate mocks base method.
 func (m *MockProductService) VariantsBulkCreate(arg0 context.Context, arg1 string, arg2 []model.ProductVariantsBulkInput) error {
 	m.ctrl.T.Helper()
@@ -138,6 +370,20 @@ func (mr *MockProductServiceMockRecorder) VariantsBulkCreate(arg0, arg1, arg2 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VariantsBulkCreate", reflect.TypeOf((*MockProductService)(nil).VariantsBulkCreate), arg0, arg1, arg2)
 }
 
+// VariantsBulkDelete mocks base method.
+func (m *MockProductService) VariantsBulkDelete(arg0 context.Context, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VariantsBulkDelete", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VariantsBulkDelete indicates an expected call of VariantsBulkDelete.
+func (mr *MockProductServiceMockRecorder) VariantsBulkDelete(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VariantsBulkDelete", reflect.TypeOf((*MockProductService)(nil).VariantsBulkDelete), arg0, arg1, arg2)
+}
+
 // VariantsBulkReorder mocks base method.
 func (m *MockProductService) VariantsBulkReorder(arg0 context.Context, arg1 string, arg2 []model.ProductVariantPositionInput) error {
 	m.ctrl.T.Helper()
@@ -165,3 +411,18 @@ func (mr *MockProductServiceMockRecorder) VariantsBulkUpdate(arg0, arg1, arg2 in
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VariantsBulkUpdate", reflect.TypeOf((*MockProductService)(nil).VariantsBulkUpdate), arg0, arg1, arg2)
 }
+
+// WaitForMedia mocks base method.
+func (m *MockProductService) WaitForMedia(arg0 context.Context, arg1 []string, arg2 time.Duration) ([]*shopify.ProductMedia, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForMedia", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*shopify.ProductMedia)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForMedia indicates an expected call of WaitForMedia.
+func (mr *MockProductServiceMockRecorder) WaitForMedia(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForMedia", reflect.TypeOf((*MockProductService)(nil).WaitForMedia), arg0, arg1, arg2)
+}
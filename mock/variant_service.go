@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	shopify "github.com/sogko/go-shopify-graphql"
 	model "github.com/sogko/go-shopify-graphql/model"
 )
 
@@ -35,6 +36,96 @@ func (m *MockVariantService) EXPECT() *MockVariantServiceMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockVariantService) Delete(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockVariantServiceMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVariantService)(nil).Delete), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockVariantService) Get(arg0 context.Context, arg1 string, arg2 ...shopify.QueryOption) (*model.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Get", varargs...)
+	ret0, _ := ret[0].(*model.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockVariantServiceMockRecorder) Get(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockVariantService)(nil).Get), varargs...)
+}
+
+// GetByBarcode mocks base method.
+func (m *MockVariantService) GetByBarcode(arg0 context.Context, arg1 string, arg2 ...shopify.QueryOption) (*model.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetByBarcode", varargs...)
+	ret0, _ := ret[0].(*model.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByBarcode indicates an expected call of GetByBarcode.
+func (mr *MockVariantServiceMockRecorder) GetByBarcode(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByBarcode", reflect.TypeOf((*MockVariantService)(nil).GetByBarcode), varargs...)
+}
+
+// GetBySKU mocks base method.
+func (m *MockVariantService) GetBySKU(arg0 context.Context, arg1 string, arg2 ...shopify.QueryOption) (*model.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetBySKU", varargs...)
+	ret0, _ := ret[0].(*model.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySKU indicates an expected call of GetBySKU.
+func (mr *MockVariantServiceMockRecorder) GetBySKU(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySKU", reflect.TypeOf((*MockVariantService)(nil).GetBySKU), varargs...)
+}
+
+// ListPage mocks base method.
+func (m *MockVariantService) ListPage(arg0 context.Context, arg1 shopify.ListOptions) ([]*model.ProductVariant, *model.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPage", arg0, arg1)
+	ret0, _ := ret[0].([]*model.ProductVariant)
+	ret1, _ := ret[1].(*model.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPage indicates an expected call of ListPage.
+func (mr *MockVariantServiceMockRecorder) ListPage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPage", reflect.TypeOf((*MockVariantService)(nil).ListPage), arg0, arg1)
+}
+
 // Update mocks base method.
 func (m *MockVariantService) Update(arg0 context.Context, arg1 model.ProductVariantInput) error {
 	m.ctrl.T.Helper()
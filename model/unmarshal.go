@@ -76,3 +76,51 @@ func decodeMedia(node map[string]interface{}) (Media, error) {
 	}
 	return nil, fmt.Errorf("must query id to decode Media")
 }
+
+func (s *WebhookSubscription) UnmarshalJSON(b []byte) error {
+	type webhookSubscription WebhookSubscription
+	var sub struct {
+		webhookSubscription
+		Endpoint json.RawMessage `json:"endpoint,omitempty"`
+	}
+	err := json.Unmarshal(b, &sub)
+	if err != nil {
+		return err
+	}
+	*s = WebhookSubscription(sub.webhookSubscription)
+	s.Endpoint, err = decodeWebhookSubscriptionEndpoint(sub.Endpoint)
+	if err != nil {
+		return fmt.Errorf("decode webhook subscription endpoint: %w", err)
+	}
+	return nil
+}
+
+func decodeWebhookSubscriptionEndpoint(b json.RawMessage) (WebhookSubscriptionEndpoint, error) {
+	if len(b) == 0 || string(b) == "null" {
+		return nil, nil
+	}
+	var typename struct {
+		Typename string `json:"__typename"`
+	}
+	err := json.Unmarshal(b, &typename)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoint WebhookSubscriptionEndpoint
+	switch typename.Typename {
+	case "WebhookEventBridgeEndpoint":
+		endpoint = &WebhookEventBridgeEndpoint{}
+	case "WebhookHttpEndpoint":
+		endpoint = &WebhookHTTPEndpoint{}
+	case "WebhookPubSubEndpoint":
+		endpoint = &WebhookPubSubEndpoint{}
+	default:
+		return nil, fmt.Errorf("must query __typename to decode WebhookSubscriptionEndpoint, got `%s`", typename.Typename)
+	}
+	err = json.Unmarshal(b, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
@@ -60,3 +60,19 @@ func WithTransport(transport http.RoundTripper) Option {
 		c.transport = transport
 	}
 }
+
+// WithBulkCheckpoint optionally sets the store checkpointing the bulk query in flight, so BulkQuery resumes the
+// operation posted by a previous process instead of posting the query again.
+func WithBulkCheckpoint(store BulkCheckpointStore) Option {
+	return func(c *Client) {
+		c.bulkCheckpoint = store
+	}
+}
+
+// WithBulkQueryLock optionally sets the lock serialising the bulk queries of the shop. By default the bulk queries
+// are only serialised within the process.
+func WithBulkQueryLock(lock BulkQueryLock) Option {
+	return func(c *Client) {
+		c.bulkQueryLock = lock
+	}
+}
@@ -3,7 +3,7 @@ package shopify
 import (
 	"context"
 	"fmt"
-	"strings"
+	"reflect"
 
 	"github.com/vinhluan/go-graphql-client"
 	"github.com/sogko/go-shopify-graphql/model"
@@ -11,7 +11,7 @@ import (
 
 //go:generate mockgen -destination=./mock/order_service.go -package=mock . OrderService
 type OrderService interface {
-	Get(ctx context.Context, id graphql.ID) (*model.Order, error)
+	Get(ctx context.Context, id graphql.ID, opts ...QueryOption) (*model.Order, error)
 
 	List(ctx context.Context, opts ListOptions) ([]*model.Order, error)
 	ListAll(ctx context.Context) ([]*model.Order, error)
@@ -27,6 +27,8 @@ type OrderServiceOp struct {
 
 var _ OrderService = &OrderServiceOp{}
 
+var orderType = reflect.TypeOf(model.Order{})
+
 type mutationOrderUpdate struct {
 	OrderUpdateResult struct {
 		UserErrors []model.UserError `json:"userErrors,omitempty"`
@@ -218,45 +220,59 @@ fragment lineItem on LineItem {
 }
 `
 
-func (s *OrderServiceOp) Get(ctx context.Context, id graphql.ID) (*model.Order, error) {
+// orderGetQuery selects the default fields of Order.Get, with the lineItem fragment.
+var orderGetQuery = fmt.Sprintf(`
+	%s
+	lineItems(first:50){
+		edges{
+			node{
+				...lineItem
+			}
+		}
+	}
+	fulfillmentOrders(first:5){
+		edges {
+			node {
+				id
+				status
+				lineItems(first:50){
+					edges {
+						node {
+							id
+							remainingQuantity
+							totalQuantity
+							lineItem{
+								sku
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+`, orderBaseQuery)
+
+func (s *OrderServiceOp) Get(ctx context.Context, id graphql.ID, opts ...QueryOption) (*model.Order, error) {
+	fields, err := selectFields(orderType, newQueryOptions(opts).fields, "")
+	if err != nil {
+		return nil, err
+	}
+	fragments := ""
+	if fields == "" {
+		fields, fragments = orderGetQuery, lineItemFragment
+	}
+
 	q := fmt.Sprintf(`
 		query order($id: ID!) {
 			node(id: $id){
 				... on Order {
 					%s
-					lineItems(first:50){
-						edges{
-							node{
-								...lineItem
-							}
-						}
-					}
-					fulfillmentOrders(first:5){
-						edges {
-							node {
-								id
-								status
-								lineItems(first:50){
-									edges {
-										node {
-											id
-											remainingQuantity
-											totalQuantity
-											lineItem{
-												sku
-											}
-										}
-									}
-								}
-							}
-						}
-					}
 				}
 			}
 		}
 
 		%s
-	`, orderBaseQuery, lineItemFragment)
+	`, fields, fragments)
 
 	vars := map[string]interface{}{
 		"id": id,
@@ -265,7 +281,7 @@ func (s *OrderServiceOp) Get(ctx context.Context, id graphql.ID) (*model.Order,
 	out := struct {
 		Order *model.Order `json:"node"`
 	}{}
-	err := s.client.QueryString(ctx, q, vars, &out)
+	err = s.client.QueryString(ctx, q, vars, &out)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
@@ -273,32 +289,44 @@ func (s *OrderServiceOp) Get(ctx context.Context, id graphql.ID) (*model.Order,
 	return out.Order, nil
 }
 
+// orderBulkQuery selects the default fields of Order.List, with the lineItem fragment.
+var orderBulkQuery = fmt.Sprintf(`
+	%s
+	lineItems{
+		edges{
+			node{
+				...lineItem
+			}
+		}
+	}
+`, orderBaseQuery)
+
 func (s *OrderServiceOp) List(ctx context.Context, opts ListOptions) ([]*model.Order, error) {
+	fields, err := selectFields(orderType, opts.Fields, "")
+	if err != nil {
+		return nil, err
+	}
+	fragments := ""
+	if fields == "" {
+		fields, fragments = orderBulkQuery, lineItemFragment
+	}
+
 	q := fmt.Sprintf(`
 		{
-			orders(query: "$query"){
+			orders%s{
 				edges{
 					node{
 						%s
-						lineItems{
-							edges{
-								node{
-									...lineItem
-								}
-							}
-						}
 					}
 				}
 			}
 		}
 
 		%s
-	`, orderBaseQuery, lineItemFragment)
-
-	q = strings.ReplaceAll(q, "$query", opts.Query)
+	`, searchArgument(opts.Query), fields, fragments)
 
 	res := []*model.Order{}
-	err := s.client.BulkOperation.BulkQuery(ctx, q, &res)
+	err = s.client.BulkOperation.BulkQuery(ctx, q, &res)
 	if err != nil {
 		return nil, fmt.Errorf("bulk query: %w", err)
 	}
@@ -309,7 +337,7 @@ func (s *OrderServiceOp) List(ctx context.Context, opts ListOptions) ([]*model.O
 func (s *OrderServiceOp) ListAll(ctx context.Context) ([]*model.Order, error) {
 	q := fmt.Sprintf(`
 		{
-			orders(query: "$query"){
+			orders{
 				edges{
 					node{
 						%s
@@ -365,9 +393,11 @@ func (s *OrderServiceOp) ListAfterCursor(ctx context.Context, opts ListOptions)
 	`, orderLightQuery, lineItemFragmentLight)
 
 	vars := map[string]interface{}{
-		"query":   opts.Query,
 		"reverse": opts.Reverse,
 	}
+	if opts.Query != "" {
+		vars["query"] = opts.Query
+	}
 
 	if opts.After != "" {
 		vars["after"] = opts.After
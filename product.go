@@ -3,25 +3,58 @@ package shopify
 import (
 	"context"
 	"fmt"
-	"strings"
+	"reflect"
+	"time"
 
 	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/vinhluan/go-graphql-client"
 )
 
 //go:generate mockgen -destination=./mock/product_service.go -package=mock . ProductService
 type ProductService interface {
-	List(ctx context.Context, query string) ([]*model.Product, error)
+	List(ctx context.Context, query string, opts ...QueryOption) ([]*model.Product, error)
 	ListAll(ctx context.Context) ([]*model.Product, error)
+	// ListPage returns a page of the products, without running a bulk operation, and the info to fetch its
+	// neighbouring pages with ListOptions.After or ListOptions.Before.
+	ListPage(ctx context.Context, opts ListOptions) ([]*model.Product, *model.PageInfo, error)
 
-	Get(ctx context.Context, id string) (*model.Product, error)
+	Get(ctx context.Context, id string, opts ...QueryOption) (*model.Product, error)
 
 	Create(ctx context.Context, product model.ProductInput, media []model.CreateMediaInput) (*model.Product, error)
 	Update(ctx context.Context, product model.ProductInput) (*model.Product, error)
 	Delete(ctx context.Context, product model.ProductDeleteInput) error
+	// Upsert creates the product, or updates the product matching the key with its variants, and reports which
+	// action it took.
+	Upsert(ctx context.Context, key ProductUpsertKey, product ProductUpsert) (*ProductUpsertResult, error)
+	// Sync compares the desired products with the products of the shop and sends only the mutations changing the
+	// fields that differ, or with WithDryRun only reports the planned changes.
+	Sync(ctx context.Context, products []model.ProductInput, opts ...SyncOption) (*SyncReport, error)
+
+	// The bundled API version has no product option mutations, so the options are changed by updating the product
+	// with the option names and the option values of every variant.
+	CreateOption(ctx context.Context, productID string, name string, value string) (*model.Product, error)
+	UpdateOption(ctx context.Context, productID string, name string, update ProductOptionUpdate) (*model.Product, error)
+	DeleteOption(ctx context.Context, productID string, name string) (*model.Product, error)
+	ReorderOptions(ctx context.Context, productID string, names []string) (*model.Product, error)
+	ReorderOptionValues(ctx context.Context, productID string, name string, values []string) error
+	// SyncVariantMatrix creates the missing variants of the matrix and updates the SKU and price of the others.
+	SyncVariantMatrix(ctx context.Context, productID string, matrix VariantMatrix) (*VariantMatrixResult, error)
 
 	VariantsBulkCreate(ctx context.Context, id string, input []model.ProductVariantsBulkInput) error
 	VariantsBulkUpdate(ctx context.Context, id string, input []model.ProductVariantsBulkInput) error
 	VariantsBulkReorder(ctx context.Context, id string, input []model.ProductVariantPositionInput) error
+	// VariantsBulkDelete deletes the variants with the IDs from the product.
+	VariantsBulkDelete(ctx context.Context, id string, variantIDs []string) error
+
+	CreateMedia(ctx context.Context, productID string, media []model.CreateMediaInput) ([]*ProductMedia, error)
+	UpdateMedia(ctx context.Context, productID string, media []model.UpdateMediaInput) ([]*ProductMedia, error)
+	DeleteMedia(ctx context.Context, productID string, mediaIDs []string) ([]string, error)
+	ReorderMedia(ctx context.Context, productID string, moves []model.MoveInput) (*model.Job, error)
+	// WaitForMedia polls the media every interval until they're all READY or FAILED.
+	WaitForMedia(ctx context.Context, mediaIDs []string, interval time.Duration) ([]*ProductMedia, error)
+	// UploadMedia uploads the local files to staged targets, creates the media of the product from them and waits
+	// until they're processed. When some of them failed, the media are returned with ErrMediaFailed.
+	UploadMedia(ctx context.Context, productID string, uploads ...MediaUpload) ([]*ProductMedia, error)
 }
 
 type ProductServiceOp struct {
@@ -30,16 +63,20 @@ type ProductServiceOp struct {
 
 var _ ProductService = &ProductServiceOp{}
 
+var productType = reflect.TypeOf(model.Product{})
+
 type mutationProductCreate struct {
-	ProductCreateResult model.ProductCreatePayload `graphql:"productCreate(input: $input, media: $media)" json:"productCreate"`
+	ProductCreateResult model.ProductCreatePayload `json:"productCreate"`
 }
 
 type mutationProductUpdate struct {
-	ProductUpdateResult model.ProductUpdatePayload `graphql:"productUpdate(input: $input)" json:"productUpdate"`
+	ProductUpdateResult model.ProductUpdatePayload `json:"productUpdate"`
 }
 
 type mutationProductDelete struct {
-	ProductDeleteResult model.ProductDeletePayload `graphql:"productDelete(input: $input)" json:"productDelete"`
+	ProductDeleteResult struct {
+		UserErrors []model.UserError `json:"userErrors,omitempty"`
+	} `graphql:"productDelete(input: $input)" json:"productDelete"`
 }
 
 type mutationProductVariantsBulkCreate struct {
@@ -60,6 +97,12 @@ type mutationProductVariantsBulkReorder struct {
 	} `graphql:"productVariantsBulkReorder(positions: $positions, productId: $productId)" json:"productVariantsBulkReorder"`
 }
 
+type mutationProductVariantsBulkDelete struct {
+	ProductVariantsBulkDeleteResult struct {
+		UserErrors []model.UserError `json:"userErrors,omitempty"`
+	} `graphql:"productVariantsBulkDelete(productId: $productId, variantsIds: $variantsIds)" json:"productVariantsBulkDelete"`
+}
+
 const productBaseQuery = `
 	id
 	legacyResourceId
@@ -141,6 +184,35 @@ var productQuery = fmt.Sprintf(`
 	}
 `, productBaseQuery)
 
+// NOTE: Product mutations are sent as strings, the graphql client would otherwise write the whole model.Product graph
+var productCreateMutation = fmt.Sprintf(`
+	mutation productCreate($input: ProductInput!, $media: [CreateMediaInput!]) {
+		productCreate(input: $input, media: $media){
+			product{
+				%s
+			}
+			userErrors{
+				field
+				message
+			}
+		}
+	}
+`, productBaseQuery)
+
+var productUpdateMutation = fmt.Sprintf(`
+	mutation productUpdate($input: ProductInput!) {
+		productUpdate(input: $input){
+			product{
+				%s
+			}
+			userErrors{
+				field
+				message
+			}
+		}
+	}
+`, productBaseQuery)
+
 var productBulkQuery = fmt.Sprintf(`
 	%s
 	metafields{
@@ -210,10 +282,15 @@ func (s *ProductServiceOp) ListAll(ctx context.Context) ([]*model.Product, error
 	return res, nil
 }
 
-func (s *ProductServiceOp) List(ctx context.Context, query string) ([]*model.Product, error) {
+func (s *ProductServiceOp) List(ctx context.Context, query string, opts ...QueryOption) ([]*model.Product, error) {
+	fields, err := selectFields(productType, newQueryOptions(opts).fields, productBulkQuery)
+	if err != nil {
+		return nil, err
+	}
+
 	q := fmt.Sprintf(`
 		{
-			products(query: "$query"){
+			products%s{
 				edges{
 					node{
 						%s
@@ -221,12 +298,10 @@ func (s *ProductServiceOp) List(ctx context.Context, query string) ([]*model.Pro
 				}
 			}
 		}
-	`, productBulkQuery)
-
-	q = strings.ReplaceAll(q, "$query", query)
+	`, searchArgument(query), fields)
 
 	res := []*model.Product{}
-	err := s.client.BulkOperation.BulkQuery(ctx, q, &res)
+	err = s.client.BulkOperation.BulkQuery(ctx, q, &res)
 	if err != nil {
 		return nil, fmt.Errorf("bulk query: %w", err)
 	}
@@ -234,11 +309,79 @@ func (s *ProductServiceOp) List(ctx context.Context, query string) ([]*model.Pro
 	return res, nil
 }
 
-func (s *ProductServiceOp) Get(ctx context.Context, id string) (*model.Product, error) {
+func (s *ProductServiceOp) ListPage(ctx context.Context, opts ListOptions) ([]*model.Product, *model.PageInfo, error) {
+	fields, err := selectFields(productType, opts.Fields, productBaseQuery)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q := fmt.Sprintf(`
+		query products($query: String, $first: Int, $last: Int, $before: String, $after: String, $reverse: Boolean, $sortKey: ProductSortKeys) {
+			products(query: $query, first: $first, last: $last, before: $before, after: $after, reverse: $reverse, sortKey: $sortKey){
+				edges{
+					node{
+						%s
+					}
+				}
+				pageInfo{
+					hasNextPage
+					hasPreviousPage
+					startCursor
+					endCursor
+				}
+			}
+		}
+	`, fields)
+
+	vars, err := listPageVariables(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.SortKey != "" {
+		sortKey := model.ProductSortKeys(opts.SortKey)
+		if !sortKey.IsValid() {
+			return nil, nil, fmt.Errorf("invalid product sort key `%s`", opts.SortKey)
+		}
+		vars["sortKey"] = sortKey
+	}
+
+	out := struct {
+		Products struct {
+			Edges []struct {
+				Node *model.Product `json:"node,omitempty"`
+			} `json:"edges,omitempty"`
+			PageInfo model.PageInfo `json:"pageInfo"`
+		} `json:"products"`
+	}{}
+	err = s.client.QueryString(ctx, q, vars, &out)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query: %w", err)
+	}
+
+	res := make([]*model.Product, 0, len(out.Products.Edges))
+	for _, edge := range out.Products.Edges {
+		res = append(res, edge.Node)
+	}
+
+	return res, &out.Products.PageInfo, nil
+}
+
+func (s *ProductServiceOp) Get(ctx context.Context, id string, opts ...QueryOption) (*model.Product, error) {
+	fields, err := selectFields(productType, newQueryOptions(opts).fields, "")
+	if err != nil {
+		return nil, err
+	}
+	if fields != "" {
+		return s.getFields(ctx, id, fields)
+	}
+
 	out, err := s.getPage(ctx, id, "")
 	if err != nil {
 		return nil, err
 	}
+	if out == nil {
+		return nil, nil
+	}
 
 	nextPageData := out
 	hasNextPage := out.Variants.PageInfo.HasNextPage
@@ -282,6 +425,31 @@ func (s *ProductServiceOp) getPage(ctx context.Context, id string, cursor string
 	return out.Product, nil
 }
 
+// getFields returns the product with the selected fields, as they're selected.
+func (s *ProductServiceOp) getFields(ctx context.Context, id string, fields string) (*model.Product, error) {
+	q := fmt.Sprintf(`
+		query product($id: ID!) {
+			product(id: $id){
+				%s
+			}
+		}
+	`, fields)
+
+	vars := map[string]interface{}{
+		"id": id,
+	}
+
+	out := struct {
+		Product *model.Product `json:"product"`
+	}{}
+	err := s.client.QueryString(ctx, q, vars, &out)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return out.Product, nil
+}
+
 func (s *ProductServiceOp) Create(ctx context.Context, product model.ProductInput, media []model.CreateMediaInput) (*model.Product, error) {
 	m := mutationProductCreate{}
 
@@ -290,7 +458,7 @@ func (s *ProductServiceOp) Create(ctx context.Context, product model.ProductInpu
 		"media": media,
 	}
 
-	err := s.client.Mutate(ctx, &m, vars)
+	err := s.client.MutateString(ctx, productCreateMutation, vars, &m)
 	if err != nil {
 		return nil, fmt.Errorf("mutation: %w", err)
 	}
@@ -308,7 +476,7 @@ func (s *ProductServiceOp) Update(ctx context.Context, product model.ProductInpu
 	vars := map[string]interface{}{
 		"input": product,
 	}
-	err := s.client.Mutate(ctx, &m, vars)
+	err := s.client.MutateString(ctx, productUpdateMutation, vars, &m)
 	if err != nil {
 		return nil, fmt.Errorf("mutation: %w", err)
 	}
@@ -394,3 +562,26 @@ func (s *ProductServiceOp) VariantsBulkReorder(ctx context.Context, id string, i
 
 	return nil
 }
+
+func (s *ProductServiceOp) VariantsBulkDelete(ctx context.Context, id string, variantIDs []string) error {
+	m := mutationProductVariantsBulkDelete{}
+
+	ids := make([]graphql.ID, len(variantIDs))
+	for i, variantID := range variantIDs {
+		ids[i] = graphql.ID(variantID)
+	}
+	vars := map[string]interface{}{
+		"productId":   graphql.ID(id),
+		"variantsIds": ids,
+	}
+	err := s.client.Mutate(ctx, &m, vars)
+	if err != nil {
+		return fmt.Errorf("mutation: %w", err)
+	}
+
+	if len(m.ProductVariantsBulkDeleteResult.UserErrors) > 0 {
+		return fmt.Errorf("%+v", m.ProductVariantsBulkDeleteResult.UserErrors)
+	}
+
+	return nil
+}
@@ -0,0 +1,478 @@
+package shopify
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sogko/go-shopify-graphql/model"
+)
+
+// ErrMediaFailed is returned when some of the uploaded media failed to be processed, see ProductMedia.MediaErrors.
+var ErrMediaFailed = errors.New("media processing failed")
+
+// mediaPollInterval is the interval between the polls of the status of the uploaded media.
+var mediaPollInterval = 1 * time.Second
+
+// ProductMedia is a media item of a product, an image, a video or a 3D model.
+type ProductMedia struct {
+	ID               string                   `json:"id"`
+	Alt              *string                  `json:"alt,omitempty"`
+	MediaContentType model.MediaContentType   `json:"mediaContentType"`
+	Status           model.MediaStatus        `json:"status"`
+	MediaErrors      []model.MediaError       `json:"mediaErrors,omitempty"`
+	Preview          *model.MediaPreviewImage `json:"preview,omitempty"`
+}
+
+// IsProcessed reports whether the media is READY or FAILED.
+func (m *ProductMedia) IsProcessed() bool {
+	return m.Status == model.MediaStatusReady || m.Status == model.MediaStatusFailed
+}
+
+// MediaUpload is a local file to upload as a media item of a product.
+type MediaUpload struct {
+	// Reader reads the file, or else the file is read from Path.
+	Reader io.Reader
+	// Size is the size of the content of Reader, taken from Reader by default when it has a Len method, like
+	// bytes.Reader, or is a file. Videos and 3D models can't be uploaded from a Reader of unknown size.
+	Size int64
+	Path string
+	// Filename is the name of the uploaded file, the base of Path by default.
+	Filename string
+	// MimeType is the type of the file, detected from its extension or its content by default.
+	MimeType string
+	// MediaContentType is the type of the media, derived from MimeType by default.
+	MediaContentType model.MediaContentType
+	Alt              string
+}
+
+const productMediaQuery = `
+	id
+	alt
+	mediaContentType
+	status
+	mediaErrors{
+		code
+		details
+		message
+	}
+	preview{
+		image{
+			id
+			altText
+			height
+			width
+			url
+		}
+		status
+	}
+`
+
+var productCreateMediaMutation = fmt.Sprintf(`
+	mutation productCreateMedia($productId: ID!, $media: [CreateMediaInput!]!) {
+		productCreateMedia(productId: $productId, media: $media){
+			media{
+				%s
+			}
+			mediaUserErrors{
+				code
+				field
+				message
+			}
+		}
+	}
+`, productMediaQuery)
+
+var productUpdateMediaMutation = fmt.Sprintf(`
+	mutation productUpdateMedia($productId: ID!, $media: [UpdateMediaInput!]!) {
+		productUpdateMedia(productId: $productId, media: $media){
+			media{
+				%s
+			}
+			mediaUserErrors{
+				code
+				field
+				message
+			}
+		}
+	}
+`, productMediaQuery)
+
+const productDeleteMediaMutation = `
+	mutation productDeleteMedia($productId: ID!, $mediaIds: [ID!]!) {
+		productDeleteMedia(productId: $productId, mediaIds: $mediaIds){
+			deletedMediaIds
+			mediaUserErrors{
+				code
+				field
+				message
+			}
+		}
+	}
+`
+
+const productReorderMediaMutation = `
+	mutation productReorderMedia($id: ID!, $moves: [MoveInput!]!) {
+		productReorderMedia(id: $id, moves: $moves){
+			job{
+				id
+				done
+			}
+			mediaUserErrors{
+				code
+				field
+				message
+			}
+		}
+	}
+`
+
+type mutationProductCreateMedia struct {
+	ProductCreateMediaResult struct {
+		Media           []*ProductMedia        `json:"media,omitempty"`
+		MediaUserErrors []model.MediaUserError `json:"mediaUserErrors,omitempty"`
+	} `json:"productCreateMedia"`
+}
+
+type mutationProductUpdateMedia struct {
+	ProductUpdateMediaResult struct {
+		Media           []*ProductMedia        `json:"media,omitempty"`
+		MediaUserErrors []model.MediaUserError `json:"mediaUserErrors,omitempty"`
+	} `json:"productUpdateMedia"`
+}
+
+type mutationProductDeleteMedia struct {
+	ProductDeleteMediaResult struct {
+		DeletedMediaIds []string               `json:"deletedMediaIds,omitempty"`
+		MediaUserErrors []model.MediaUserError `json:"mediaUserErrors,omitempty"`
+	} `json:"productDeleteMedia"`
+}
+
+type mutationProductReorderMedia struct {
+	ProductReorderMediaResult struct {
+		Job             *model.Job             `json:"job,omitempty"`
+		MediaUserErrors []model.MediaUserError `json:"mediaUserErrors,omitempty"`
+	} `json:"productReorderMedia"`
+}
+
+func (s *ProductServiceOp) CreateMedia(ctx context.Context, productID string, media []model.CreateMediaInput) ([]*ProductMedia, error) {
+	m := mutationProductCreateMedia{}
+	vars := map[string]interface{}{
+		"productId": productID,
+		"media":     media,
+	}
+
+	err := s.client.MutateString(ctx, productCreateMediaMutation, vars, &m)
+	if err != nil {
+		return nil, fmt.Errorf("mutation: %w", err)
+	}
+	if len(m.ProductCreateMediaResult.MediaUserErrors) > 0 {
+		return nil, fmt.Errorf("%+v", m.ProductCreateMediaResult.MediaUserErrors)
+	}
+
+	return m.ProductCreateMediaResult.Media, nil
+}
+
+func (s *ProductServiceOp) UpdateMedia(ctx context.Context, productID string, media []model.UpdateMediaInput) ([]*ProductMedia, error) {
+	m := mutationProductUpdateMedia{}
+	vars := map[string]interface{}{
+		"productId": productID,
+		"media":     media,
+	}
+
+	err := s.client.MutateString(ctx, productUpdateMediaMutation, vars, &m)
+	if err != nil {
+		return nil, fmt.Errorf("mutation: %w", err)
+	}
+	if len(m.ProductUpdateMediaResult.MediaUserErrors) > 0 {
+		return nil, fmt.Errorf("%+v", m.ProductUpdateMediaResult.MediaUserErrors)
+	}
+
+	return m.ProductUpdateMediaResult.Media, nil
+}
+
+func (s *ProductServiceOp) DeleteMedia(ctx context.Context, productID string, mediaIDs []string) ([]string, error) {
+	m := mutationProductDeleteMedia{}
+	vars := map[string]interface{}{
+		"productId": productID,
+		"mediaIds":  mediaIDs,
+	}
+
+	err := s.client.MutateString(ctx, productDeleteMediaMutation, vars, &m)
+	if err != nil {
+		return nil, fmt.Errorf("mutation: %w", err)
+	}
+	if len(m.ProductDeleteMediaResult.MediaUserErrors) > 0 {
+		return nil, fmt.Errorf("%+v", m.ProductDeleteMediaResult.MediaUserErrors)
+	}
+
+	return m.ProductDeleteMediaResult.DeletedMediaIds, nil
+}
+
+func (s *ProductServiceOp) ReorderMedia(ctx context.Context, productID string, moves []model.MoveInput) (*model.Job, error) {
+	m := mutationProductReorderMedia{}
+	vars := map[string]interface{}{
+		"id":    productID,
+		"moves": moves,
+	}
+
+	err := s.client.MutateString(ctx, productReorderMediaMutation, vars, &m)
+	if err != nil {
+		return nil, fmt.Errorf("mutation: %w", err)
+	}
+	if len(m.ProductReorderMediaResult.MediaUserErrors) > 0 {
+		return nil, fmt.Errorf("%+v", m.ProductReorderMediaResult.MediaUserErrors)
+	}
+
+	return m.ProductReorderMediaResult.Job, nil
+}
+
+func (s *ProductServiceOp) WaitForMedia(ctx context.Context, mediaIDs []string, interval time.Duration) ([]*ProductMedia, error) {
+	for {
+		media, err := s.getMedia(ctx, mediaIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		processed := true
+		for _, m := range media {
+			processed = processed && m.IsProcessed()
+		}
+		if processed {
+			return media, nil
+		}
+		log.Debugf("Media of %d items still processing...", len(media))
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return media, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (s *ProductServiceOp) getMedia(ctx context.Context, mediaIDs []string) ([]*ProductMedia, error) {
+	q := fmt.Sprintf(`
+		query media($ids: [ID!]!) {
+			nodes(ids: $ids){
+				... on Media {
+					%s
+				}
+			}
+		}
+	`, productMediaQuery)
+
+	vars := map[string]interface{}{
+		"ids": mediaIDs,
+	}
+
+	out := struct {
+		Nodes []*ProductMedia `json:"nodes"`
+	}{}
+	err := s.client.QueryString(ctx, q, vars, &out)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	for i, m := range out.Nodes {
+		if m == nil || m.ID == "" {
+			return nil, fmt.Errorf("media %s not found", mediaIDs[i])
+		}
+	}
+	return out.Nodes, nil
+}
+
+func (s *ProductServiceOp) UploadMedia(ctx context.Context, productID string, uploads ...MediaUpload) ([]*ProductMedia, error) {
+	inputs := make([]model.CreateMediaInput, len(uploads))
+	for i, upload := range uploads {
+		input, err := s.stageMedia(ctx, upload)
+		if err != nil {
+			return nil, fmt.Errorf("upload %s: %w", upload.name(), err)
+		}
+		inputs[i] = *input
+	}
+
+	created, err := s.CreateMedia(ctx, productID, inputs)
+	if err != nil {
+		return nil, fmt.Errorf("create media: %w", err)
+	}
+	ids := make([]string, len(created))
+	for i, m := range created {
+		ids[i] = m.ID
+	}
+
+	media, err := s.WaitForMedia(ctx, ids, mediaPollInterval)
+	if err != nil {
+		return media, fmt.Errorf("wait for media: %w", err)
+	}
+
+	var failed []string
+	for _, m := range media {
+		if m.Status == model.MediaStatusFailed {
+			failed = append(failed, m.ID)
+		}
+	}
+	if len(failed) > 0 {
+		return media, fmt.Errorf("%w: %s", ErrMediaFailed, strings.Join(failed, ", "))
+	}
+	return media, nil
+}
+
+// stageMedia streams the file to a staged target and returns the input creating the media from it.
+func (s *ProductServiceOp) stageMedia(ctx context.Context, upload MediaUpload) (*model.CreateMediaInput, error) {
+	file, size, err := upload.open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	filename := upload.name()
+	mimeType := upload.MimeType
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(filepath.Ext(filename))
+	}
+	// The content is sniffed from a buffered head, so it's still read once.
+	r := bufio.NewReaderSize(file, 512)
+	if mimeType == "" {
+		head, err := r.Peek(512)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		mimeType = http.DetectContentType(head)
+	}
+	contentType := upload.MediaContentType
+	if contentType == "" {
+		contentType, err = mediaContentType(mimeType)
+		if err != nil {
+			return nil, err
+		}
+	}
+	resource, err := stagedMediaResource(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	httpMethod := model.StagedUploadHTTPMethodTypePost
+	input := model.StagedUploadInput{
+		Resource:   resource,
+		Filename:   filename,
+		MimeType:   mimeType,
+		HTTPMethod: &httpMethod,
+	}
+	if size >= 0 {
+		fileSize := strconv.FormatInt(size, 10)
+		input.FileSize = &fileSize
+	} else if resource != model.StagedUploadTargetGenerateUploadResourceImage {
+		return nil, fmt.Errorf("the Size of the Reader must be set to upload a %s", strings.ToLower(string(contentType)))
+	}
+	target, err := s.client.createStagedUpload(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("create staged upload: %w", err)
+	}
+	if target.ResourceURL == nil {
+		return nil, fmt.Errorf("staged upload target has no resource URL")
+	}
+
+	err = s.client.uploadToStagedTarget(ctx, target, filename, r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	media := &model.CreateMediaInput{
+		OriginalSource:   *target.ResourceURL,
+		MediaContentType: contentType,
+	}
+	if upload.Alt != "" {
+		media.Alt = &upload.Alt
+	}
+	return media, nil
+}
+
+func (u MediaUpload) name() string {
+	if u.Filename != "" {
+		return u.Filename
+	}
+	return filepath.Base(u.Path)
+}
+
+// open returns the content of the file with its size, -1 when it's unknown.
+func (u MediaUpload) open() (io.ReadCloser, int64, error) {
+	if u.Reader != nil {
+		size := u.Size
+		if size == 0 {
+			size = readerSize(u.Reader)
+		}
+		return io.NopCloser(u.Reader), size, nil
+	}
+	if u.Path == "" {
+		return nil, 0, fmt.Errorf("no Reader or Path to upload")
+	}
+	f, err := os.Open(u.Path)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	return f, info.Size(), nil
+}
+
+// readerSize returns the size of the content left in r, -1 when it's unknown.
+func readerSize(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		// bytes.Reader, strings.Reader and bytes.Buffer report the unread length.
+		return int64(r.Len())
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
+
+func mediaContentType(mimeType string) (model.MediaContentType, error) {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return model.MediaContentTypeImage, nil
+	case strings.HasPrefix(mimeType, "video/"):
+		return model.MediaContentTypeVideo, nil
+	case strings.HasPrefix(mimeType, "model/"):
+		return model.MediaContentTypeModel3d, nil
+	default:
+		return "", fmt.Errorf("unsupported media type `%s`, set MediaContentType", mimeType)
+	}
+}
+
+func stagedMediaResource(contentType model.MediaContentType) (model.StagedUploadTargetGenerateUploadResource, error) {
+	switch contentType {
+	case model.MediaContentTypeImage:
+		return model.StagedUploadTargetGenerateUploadResourceImage, nil
+	case model.MediaContentTypeVideo:
+		return model.StagedUploadTargetGenerateUploadResourceVideo, nil
+	case model.MediaContentTypeModel3d:
+		return model.StagedUploadTargetGenerateUploadResourceModel3d, nil
+	default:
+		return "", fmt.Errorf("media of type %s can't be uploaded", contentType)
+	}
+}
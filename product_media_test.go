@@ -0,0 +1,110 @@
+package shopify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mediaGraphQL stages uploads to uploadURL, creates the media of the inputs and answers the media queries with the
+// nodes, in order.
+func mediaGraphQL(uploadURL string, nodes ...string) *queuedGraphQL {
+	return &queuedGraphQL{responses: map[string][]string{
+		"stagedUploadsCreate": {`{"stagedTargets":[{"url":"` + uploadURL + `",` +
+			`"resourceUrl":"https://shopify-staged-uploads.storage.googleapis.com/tmp/1/shirt.png",` +
+			`"parameters":[{"name":"key","value":"tmp/1/shirt.png"}]}]}`},
+		"productCreateMedia": {`{"media":[{"id":"gid://shopify/MediaImage/1","mediaContentType":"IMAGE","status":"UPLOADED"}]}`},
+		"nodes":              nodes,
+	}}
+}
+
+func TestUploadMedia(t *testing.T) {
+	mediaPollInterval = time.Millisecond
+	defer func() { mediaPollInterval = time.Second }()
+
+	var uploaded, key string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		require.NoError(t, err)
+		b, _ := io.ReadAll(file)
+		uploaded, key = string(b), r.FormValue("key")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "shirt.png")
+	require.NoError(t, os.WriteFile(path, []byte("png data"), 0o600))
+
+	t.Run("ready", func(t *testing.T) {
+		gql := mediaGraphQL(server.URL,
+			`[{"id":"gid://shopify/MediaImage/1","mediaContentType":"IMAGE","status":"PROCESSING"}]`,
+			`[{"id":"gid://shopify/MediaImage/1","mediaContentType":"IMAGE","status":"READY","alt":"Shirt"}]`,
+		)
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		media, err := client.Product.UploadMedia(context.Background(), "gid://shopify/Product/1", MediaUpload{Path: path, Alt: "Shirt"})
+		require.NoError(t, err)
+		require.Len(t, media, 1)
+		assert.Equal(t, model.MediaStatusReady, media[0].Status)
+		assert.Len(t, gql.variables("nodes"), 2)
+
+		assert.Equal(t, "png data", uploaded)
+		assert.Equal(t, "tmp/1/shirt.png", key)
+		staged := gql.variables("stagedUploadsCreate")[0]["input"].([]model.StagedUploadInput)
+		require.Len(t, staged, 1)
+		assert.Equal(t, model.StagedUploadTargetGenerateUploadResourceImage, staged[0].Resource)
+		assert.Equal(t, "shirt.png", staged[0].Filename)
+		assert.Equal(t, "image/png", staged[0].MimeType)
+		assert.Equal(t, "8", *staged[0].FileSize)
+		created := gql.variables("productCreateMedia")[0]["media"].([]model.CreateMediaInput)
+		require.Len(t, created, 1)
+		assert.Equal(t, "https://shopify-staged-uploads.storage.googleapis.com/tmp/1/shirt.png", created[0].OriginalSource)
+		assert.Equal(t, model.MediaContentTypeImage, created[0].MediaContentType)
+		assert.Equal(t, "Shirt", *created[0].Alt)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		gql := mediaGraphQL(server.URL,
+			`[{"id":"gid://shopify/MediaImage/1","mediaContentType":"IMAGE","status":"FAILED","mediaErrors":[{"code":"INVALID_IMAGE_FILE","message":"Invalid image file"}]}]`,
+		)
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		media, err := client.Product.UploadMedia(context.Background(), "gid://shopify/Product/1", MediaUpload{Reader: strings.NewReader("not an image"), Filename: "shirt.png"})
+		assert.ErrorIs(t, err, ErrMediaFailed)
+		require.Len(t, media, 1)
+		assert.Equal(t, "Invalid image file", media[0].MediaErrors[0].Message)
+	})
+
+	t.Run("reader of unknown size", func(t *testing.T) {
+		gql := mediaGraphQL(server.URL, `[{"id":"gid://shopify/MediaImage/1","mediaContentType":"IMAGE","status":"READY"}]`)
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		_, err := client.Product.UploadMedia(context.Background(), "gid://shopify/Product/1", MediaUpload{Reader: io.MultiReader(strings.NewReader("\x89PNG\r\n\x1a\n data"))})
+		require.NoError(t, err)
+		assert.Equal(t, "\x89PNG\r\n\x1a\n data", uploaded)
+		staged := gql.variables("stagedUploadsCreate")[0]["input"].([]model.StagedUploadInput)
+		require.Len(t, staged, 1)
+		assert.Equal(t, "image/png", staged[0].MimeType)
+		assert.Nil(t, staged[0].FileSize)
+
+		_, err = client.Product.UploadMedia(context.Background(), "gid://shopify/Product/1", MediaUpload{Reader: io.MultiReader(strings.NewReader("mp4")), Filename: "shirt.mp4"})
+		assert.EqualError(t, err, "upload shirt.mp4: the Size of the Reader must be set to upload a video")
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		client := NewClient("test", WithGraphQLClient(mediaGraphQL(server.URL)))
+
+		_, err := client.Product.UploadMedia(context.Background(), "gid://shopify/Product/1", MediaUpload{Reader: strings.NewReader("text"), Filename: "notes.txt"})
+		assert.EqualError(t, err, "upload notes.txt: unsupported media type `text/plain; charset=utf-8`, set MediaContentType")
+	})
+}
@@ -0,0 +1,441 @@
+package shopify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"gopkg.in/guregu/null.v4"
+)
+
+// maxProductOptions and maxProductVariants are the most options and variants a product has.
+const (
+	maxProductOptions  = 3
+	maxProductVariants = 100
+)
+
+// ProductOptionValues is an option of a product with its values, in order.
+type ProductOptionValues struct {
+	Name   string
+	Values []string
+}
+
+// ProductOptionUpdate renames an option and its values.
+type ProductOptionUpdate struct {
+	// Name is the new name of the option, it's kept when empty.
+	Name string
+	// Values maps the current values to their new ones.
+	Values map[string]string
+}
+
+// VariantMatrix generates a variant for every combination of the values of the options.
+type VariantMatrix struct {
+	Options []ProductOptionValues
+	// SKU and Price are text/template templates executed with the values of the variant by option name, e.g.
+	// `SHIRT-{{.Size}}-{{.Color}}` or `{{if eq .Size "XL"}}12.00{{else}}10.00{{end}}`. The field is left unset
+	// when its template is empty.
+	SKU   string
+	Price string
+	// Variant holds the other fields of the generated variants.
+	Variant model.ProductVariantsBulkInput
+}
+
+// VariantMatrixResult lists the variants SyncVariantMatrix created and updated by their title, e.g. `S / Red`.
+type VariantMatrixResult struct {
+	Created []string
+	Updated []string
+	// Unmatched holds the IDs of the variants with values outside of the matrix, they're left as they are.
+	Unmatched []string
+}
+
+// Variants returns the variants of the matrix, the values of the first option changing the slowest.
+func (m VariantMatrix) Variants() ([]model.ProductVariantsBulkInput, error) {
+	if len(m.Options) == 0 || len(m.Options) > maxProductOptions {
+		return nil, fmt.Errorf("the matrix must have between 1 and %d options", maxProductOptions)
+	}
+	count := 1
+	for _, o := range m.Options {
+		if len(o.Values) == 0 {
+			return nil, fmt.Errorf("the option `%s` has no values", o.Name)
+		}
+		count *= len(o.Values)
+	}
+	if count > maxProductVariants {
+		return nil, fmt.Errorf("the matrix has %d variants, a product has at most %d", count, maxProductVariants)
+	}
+
+	sku, err := template.New("sku").Option("missingkey=error").Parse(m.SKU)
+	if err != nil {
+		return nil, fmt.Errorf("parse SKU template: %w", err)
+	}
+	price, err := template.New("price").Option("missingkey=error").Parse(m.Price)
+	if err != nil {
+		return nil, fmt.Errorf("parse price template: %w", err)
+	}
+
+	variants := make([]model.ProductVariantsBulkInput, 0, count)
+	values := make([]string, len(m.Options))
+	var generate func(i int) error
+	generate = func(i int) error {
+		if i < len(m.Options) {
+			for _, value := range m.Options[i].Values {
+				values[i] = value
+				err := generate(i + 1)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		data := make(map[string]string, len(m.Options))
+		for j, o := range m.Options {
+			data[o.Name] = values[j]
+		}
+		v := m.Variant
+		v.ID = nil
+		v.Options = append([]string(nil), values...)
+		if m.SKU != "" {
+			s, err := executeTemplate(sku, data)
+			if err != nil {
+				return fmt.Errorf("SKU of %s: %w", strings.Join(values, " / "), err)
+			}
+			v.Sku = &s
+		}
+		if m.Price != "" {
+			s, err := executeTemplate(price, data)
+			if err != nil {
+				return fmt.Errorf("price of %s: %w", strings.Join(values, " / "), err)
+			}
+			p := null.StringFrom(s)
+			v.Price = &p
+		}
+		variants = append(variants, v)
+		return nil
+	}
+
+	err = generate(0)
+	if err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+func executeTemplate(t *template.Template, data map[string]string) (string, error) {
+	var b bytes.Buffer
+	err := t.Execute(&b, data)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// SyncVariantMatrix creates the variants of the matrix the product doesn't have and updates the SKU and price of
+// those it has. The options of the matrix must be those of the product, in the same order.
+func (s *ProductServiceOp) SyncVariantMatrix(ctx context.Context, productID string, matrix VariantMatrix) (*VariantMatrixResult, error) {
+	variants, err := matrix.Variants()
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.getWithOptions(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := productOptionNames(p)
+	matrixNames := make([]string, len(matrix.Options))
+	for i, o := range matrix.Options {
+		matrixNames[i] = o.Name
+	}
+	if strings.Join(names, "\x00") != strings.Join(matrixNames, "\x00") {
+		return nil, fmt.Errorf("the matrix options %s aren't the product options %s", strings.Join(matrixNames, ", "), strings.Join(names, ", "))
+	}
+
+	existing := map[string]*model.ProductVariant{}
+	for _, v := range productVariants(p) {
+		existing[strings.Join(variantOptionValues(v, names), "\x00")] = v
+	}
+
+	res := &VariantMatrixResult{}
+	var toCreate, toUpdate []model.ProductVariantsBulkInput
+	matched := map[string]bool{}
+	for _, v := range variants {
+		title := strings.Join(v.Options, " / ")
+		current, ok := existing[strings.Join(v.Options, "\x00")]
+		if !ok {
+			toCreate = append(toCreate, v)
+			res.Created = append(res.Created, title)
+			continue
+		}
+		matched[current.ID] = true
+
+		update := model.ProductVariantsBulkInput{ID: &current.ID}
+		changed := false
+		if v.Sku != nil && *v.Sku != stringValue(current.Sku) {
+			update.Sku = v.Sku
+			changed = true
+		}
+		if v.Price != nil && !decimalEqual(v.Price.String, current.Price.String) {
+			update.Price = v.Price
+			changed = true
+		}
+		if changed {
+			toUpdate = append(toUpdate, update)
+			res.Updated = append(res.Updated, title)
+		}
+	}
+	for _, v := range productVariants(p) {
+		if !matched[v.ID] {
+			res.Unmatched = append(res.Unmatched, v.ID)
+		}
+	}
+
+	if len(toUpdate) > 0 {
+		err = s.VariantsBulkUpdate(ctx, p.ID, toUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("update variants: %w", err)
+		}
+	}
+	if len(toCreate) > 0 {
+		err = s.VariantsBulkCreate(ctx, p.ID, toCreate)
+		if err != nil {
+			return nil, fmt.Errorf("create variants: %w", err)
+		}
+	}
+
+	return res, nil
+}
+
+// CreateOption adds the option to the product, every variant taking the value.
+func (s *ProductServiceOp) CreateOption(ctx context.Context, productID string, name string, value string) (*model.Product, error) {
+	if name == "" || value == "" {
+		return nil, fmt.Errorf("the option name and value must be set")
+	}
+	p, err := s.getWithOptions(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := productOptionNames(p)
+	if len(names) >= maxProductOptions {
+		return nil, fmt.Errorf("a product has at most %d options", maxProductOptions)
+	}
+	if indexOf(names, name) >= 0 {
+		return nil, fmt.Errorf("the product already has the option `%s`", name)
+	}
+
+	return s.setOptions(ctx, p, append(names, name), func(values []string) []string {
+		return append(values, value)
+	})
+}
+
+// UpdateOption renames the option and its values.
+func (s *ProductServiceOp) UpdateOption(ctx context.Context, productID string, name string, update ProductOptionUpdate) (*model.Product, error) {
+	p, err := s.getWithOptions(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := productOptionNames(p)
+	i := indexOf(names, name)
+	if i < 0 {
+		return nil, fmt.Errorf("the product has no option `%s`", name)
+	}
+	if update.Name != "" && update.Name != name {
+		if indexOf(names, update.Name) >= 0 {
+			return nil, fmt.Errorf("the product already has the option `%s`", update.Name)
+		}
+		names[i] = update.Name
+	}
+
+	return s.setOptions(ctx, p, names, func(values []string) []string {
+		if renamed, ok := update.Values[values[i]]; ok {
+			values[i] = renamed
+		}
+		return values
+	})
+}
+
+// DeleteOption removes the option from the product, the variants must differ by their other options.
+func (s *ProductServiceOp) DeleteOption(ctx context.Context, productID string, name string) (*model.Product, error) {
+	p, err := s.getWithOptions(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := productOptionNames(p)
+	i := indexOf(names, name)
+	if i < 0 {
+		return nil, fmt.Errorf("the product has no option `%s`", name)
+	}
+	if len(names) == 1 {
+		return nil, fmt.Errorf("the only option of a product can't be deleted")
+	}
+
+	return s.setOptions(ctx, p, append(names[:i:i], names[i+1:]...), func(values []string) []string {
+		return append(values[:i:i], values[i+1:]...)
+	})
+}
+
+// ReorderOptions orders the options of the product by the names, which must be those of all its options.
+func (s *ProductServiceOp) ReorderOptions(ctx context.Context, productID string, names []string) (*model.Product, error) {
+	p, err := s.getWithOptions(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := productOptionNames(p)
+	order := make([]int, len(names))
+	for i, name := range names {
+		order[i] = indexOf(current, name)
+	}
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	sort.Strings(current)
+	if strings.Join(sorted, "\x00") != strings.Join(current, "\x00") {
+		return nil, fmt.Errorf("the options %s aren't those of the product", strings.Join(names, ", "))
+	}
+
+	return s.setOptions(ctx, p, names, func(values []string) []string {
+		reordered := make([]string, len(order))
+		for i, j := range order {
+			reordered[i] = values[j]
+		}
+		return reordered
+	})
+}
+
+// ReorderOptionValues orders the values of the option by reordering the variants, the values left out following
+// the listed ones in their current order.
+func (s *ProductServiceOp) ReorderOptionValues(ctx context.Context, productID string, name string, values []string) error {
+	p, err := s.getWithOptions(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	names := productOptionNames(p)
+	option := indexOf(names, name)
+	if option < 0 {
+		return fmt.Errorf("the product has no option `%s`", name)
+	}
+
+	// The values are ranked in the order they appear in, then the reordered option by the listed values.
+	variants := productVariants(p)
+	sort.SliceStable(variants, func(i, j int) bool { return variants[i].Position < variants[j].Position })
+	ranks := make([]map[string]int, len(names))
+	for i := range ranks {
+		ranks[i] = map[string]int{}
+	}
+	for _, v := range variants {
+		for i, value := range variantOptionValues(v, names) {
+			if _, ok := ranks[i][value]; !ok {
+				ranks[i][value] = len(values) + len(ranks[i])
+			}
+		}
+	}
+	for i, value := range values {
+		ranks[option][value] = i
+	}
+
+	sort.SliceStable(variants, func(i, j int) bool {
+		a, b := variantOptionValues(variants[i], names), variantOptionValues(variants[j], names)
+		for k := range names {
+			if ranks[k][a[k]] != ranks[k][b[k]] {
+				return ranks[k][a[k]] < ranks[k][b[k]]
+			}
+		}
+		return false
+	})
+	positions := make([]model.ProductVariantPositionInput, len(variants))
+	for i, v := range variants {
+		positions[i] = model.ProductVariantPositionInput{ID: v.ID, Position: i + 1}
+	}
+
+	err = s.VariantsBulkReorder(ctx, p.ID, positions)
+	if err != nil {
+		return fmt.Errorf("reorder variants: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ProductServiceOp) getWithOptions(ctx context.Context, productID string) (*model.Product, error) {
+	p, err := s.Get(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("get product: %w", err)
+	}
+	if p == nil {
+		return nil, fmt.Errorf("product `%s` not found", productID)
+	}
+	return p, nil
+}
+
+// setOptions updates the product with the option names and the option values of every variant, which must keep
+// the variants distinct.
+func (s *ProductServiceOp) setOptions(ctx context.Context, p *model.Product, names []string, values func([]string) []string) (*model.Product, error) {
+	current := productOptionNames(p)
+	input := model.ProductInput{ID: &p.ID, Options: names}
+	seen := map[string]bool{}
+	for _, v := range productVariants(p) {
+		options := values(variantOptionValues(v, current))
+		key := strings.Join(options, "\x00")
+		if seen[key] {
+			return nil, fmt.Errorf("several variants would have the options %s", strings.Join(options, " / "))
+		}
+		seen[key] = true
+		input.Variants = append(input.Variants, model.ProductVariantInput{ID: &v.ID, Options: options})
+	}
+
+	updated, err := s.Update(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("update product: %w", err)
+	}
+	return updated, nil
+}
+
+func productOptionNames(p *model.Product) []string {
+	options := append([]model.ProductOption(nil), p.Options...)
+	sort.SliceStable(options, func(i, j int) bool { return options[i].Position < options[j].Position })
+	names := make([]string, len(options))
+	for i, o := range options {
+		names[i] = o.Name
+	}
+	return names
+}
+
+func productVariants(p *model.Product) []*model.ProductVariant {
+	if p.Variants == nil {
+		return nil
+	}
+	variants := make([]*model.ProductVariant, 0, len(p.Variants.Edges))
+	for _, edge := range p.Variants.Edges {
+		if edge.Node != nil {
+			variants = append(variants, edge.Node)
+		}
+	}
+	return variants
+}
+
+// variantOptionValues returns the values of the options of the variant, in the order of the names.
+func variantOptionValues(v *model.ProductVariant, names []string) []string {
+	values := make([]string, len(names))
+	for _, o := range v.SelectedOptions {
+		if i := indexOf(names, o.Name); i >= 0 {
+			values[i] = o.Value
+		}
+	}
+	return values
+}
+
+func indexOf(values []string, s string) int {
+	for i, v := range values {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,183 @@
+package shopify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const optionsProduct = `{
+	"id": "gid://shopify/Product/1",
+	"options": [{"name": "Size", "position": 1}, {"name": "Color", "position": 2}],
+	"variants": {"edges": [
+		{"node": {"id": "gid://shopify/ProductVariant/1", "sku": "SHIRT-S-RED", "price": "10.00", "position": 1,
+			"selectedOptions": [{"name": "Size", "value": "S"}, {"name": "Color", "value": "Red"}]}},
+		{"node": {"id": "gid://shopify/ProductVariant/2", "sku": "SHIRT-S-BLUE", "price": "10.00", "position": 2,
+			"selectedOptions": [{"name": "Size", "value": "S"}, {"name": "Color", "value": "Blue"}]}},
+		{"node": {"id": "gid://shopify/ProductVariant/3", "sku": "SHIRT-M-RED", "price": "10.00", "position": 3,
+			"selectedOptions": [{"name": "Size", "value": "M"}, {"name": "Color", "value": "Red"}]}}
+	], "pageInfo": {"hasNextPage": false}}
+}`
+
+// optionsGraphQL answers the product queries with optionsProduct.
+func optionsGraphQL() *queuedGraphQL {
+	return &queuedGraphQL{responses: map[string][]string{
+		"product":       {optionsProduct},
+		"productUpdate": {`{"product":{"id":"gid://shopify/Product/1"}}`},
+	}}
+}
+
+// optionsUpdates returns the inputs of the product updates sent.
+func optionsUpdates(gql *queuedGraphQL) []model.ProductInput {
+	var inputs []model.ProductInput
+	for _, vars := range gql.variables("productUpdate") {
+		inputs = append(inputs, vars["input"].(model.ProductInput))
+	}
+	return inputs
+}
+
+// optionsVariants returns the variants of the bulk variant mutations sent on field.
+func optionsVariants(gql *queuedGraphQL, field string) []model.ProductVariantsBulkInput {
+	var variants []model.ProductVariantsBulkInput
+	for _, vars := range gql.variables(field) {
+		variants = append(variants, vars["variants"].([]model.ProductVariantsBulkInput)...)
+	}
+	return variants
+}
+
+func TestVariantMatrixVariants(t *testing.T) {
+	matrix := VariantMatrix{
+		Options: []ProductOptionValues{{Name: "Size", Values: []string{"S", "XL"}}, {Name: "Color", Values: []string{"Red", "Blue"}}},
+		SKU:     "SHIRT-{{.Size}}-{{.Color}}",
+		Price:   `{{if eq .Size "XL"}}12.00{{else}}10.00{{end}}`,
+	}
+
+	variants, err := matrix.Variants()
+	require.NoError(t, err)
+	require.Len(t, variants, 4)
+	assert.Equal(t, []string{"S", "Red"}, variants[0].Options)
+	assert.Equal(t, []string{"S", "Blue"}, variants[1].Options)
+	assert.Equal(t, "SHIRT-XL-Red", *variants[2].Sku)
+	assert.Equal(t, "12.00", variants[3].Price.String)
+	assert.Equal(t, "10.00", variants[0].Price.String)
+
+	matrix.SKU = "{{.Material}}"
+	_, err = matrix.Variants()
+	assert.ErrorContains(t, err, "SKU of S / Red")
+
+	_, err = VariantMatrix{Options: []ProductOptionValues{{Name: "Size"}}}.Variants()
+	assert.EqualError(t, err, "the option `Size` has no values")
+}
+
+func TestSyncVariantMatrix(t *testing.T) {
+	gql := optionsGraphQL()
+	client := NewClient("test", WithGraphQLClient(gql))
+
+	res, err := client.Product.SyncVariantMatrix(context.Background(), "gid://shopify/Product/1", VariantMatrix{
+		Options: []ProductOptionValues{{Name: "Size", Values: []string{"S", "M"}}, {Name: "Color", Values: []string{"Red"}}},
+		SKU:     "SHIRT-{{.Size}}-RED",
+		Price:   `{{if eq .Size "M"}}11{{else}}10{{end}}`,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, res.Created)
+	assert.Equal(t, []string{"M / Red"}, res.Updated)
+	assert.Equal(t, []string{"gid://shopify/ProductVariant/2"}, res.Unmatched)
+	updated := optionsVariants(gql, "productVariantsBulkUpdate")
+	require.Len(t, updated, 1)
+	assert.Equal(t, "gid://shopify/ProductVariant/3", *updated[0].ID)
+	assert.Nil(t, updated[0].Sku)
+	assert.Equal(t, "11", updated[0].Price.String)
+
+	res, err = client.Product.SyncVariantMatrix(context.Background(), "gid://shopify/Product/1", VariantMatrix{
+		Options: []ProductOptionValues{{Name: "Size", Values: []string{"L"}}, {Name: "Color", Values: []string{"Red"}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"L / Red"}, res.Created)
+	created := optionsVariants(gql, "productVariantsBulkCreate")
+	require.Len(t, created, 1)
+	assert.Equal(t, []string{"L", "Red"}, created[0].Options)
+
+	_, err = client.Product.SyncVariantMatrix(context.Background(), "gid://shopify/Product/1", VariantMatrix{
+		Options: []ProductOptionValues{{Name: "Color", Values: []string{"Red"}}, {Name: "Size", Values: []string{"S"}}},
+	})
+	assert.EqualError(t, err, "the matrix options Color, Size aren't the product options Size, Color")
+}
+
+func TestProductOptions(t *testing.T) {
+	ctx := context.Background()
+	id := "gid://shopify/Product/1"
+
+	t.Run("create", func(t *testing.T) {
+		gql := optionsGraphQL()
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		_, err := client.Product.CreateOption(ctx, id, "Material", "Cotton")
+		require.NoError(t, err)
+		updates := optionsUpdates(gql)
+		require.Len(t, updates, 1)
+		assert.Equal(t, []string{"Size", "Color", "Material"}, updates[0].Options)
+		require.Len(t, updates[0].Variants, 3)
+		assert.Equal(t, []string{"S", "Blue", "Cotton"}, updates[0].Variants[1].Options)
+		assert.Equal(t, "gid://shopify/ProductVariant/2", *updates[0].Variants[1].ID)
+
+		_, err = client.Product.CreateOption(ctx, id, "Size", "S")
+		assert.EqualError(t, err, "the product already has the option `Size`")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		gql := optionsGraphQL()
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		_, err := client.Product.UpdateOption(ctx, id, "Color", ProductOptionUpdate{Name: "Colour", Values: map[string]string{"Blue": "Navy"}})
+		require.NoError(t, err)
+		updates := optionsUpdates(gql)
+		assert.Equal(t, []string{"Size", "Colour"}, updates[0].Options)
+		assert.Equal(t, []string{"S", "Red"}, updates[0].Variants[0].Options)
+		assert.Equal(t, []string{"S", "Navy"}, updates[0].Variants[1].Options)
+
+		_, err = client.Product.UpdateOption(ctx, id, "Color", ProductOptionUpdate{Values: map[string]string{"Blue": "Red"}})
+		assert.EqualError(t, err, "several variants would have the options S / Red")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		gql := optionsGraphQL()
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		_, err := client.Product.DeleteOption(ctx, id, "Color")
+		assert.EqualError(t, err, "several variants would have the options S")
+		assert.Empty(t, optionsUpdates(gql))
+
+		_, err = client.Product.DeleteOption(ctx, id, "Size")
+		assert.EqualError(t, err, "several variants would have the options Red")
+	})
+
+	t.Run("reorder", func(t *testing.T) {
+		gql := optionsGraphQL()
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		_, err := client.Product.ReorderOptions(ctx, id, []string{"Color", "Size"})
+		require.NoError(t, err)
+		updates := optionsUpdates(gql)
+		assert.Equal(t, []string{"Color", "Size"}, updates[0].Options)
+		assert.Equal(t, []string{"Blue", "S"}, updates[0].Variants[1].Options)
+
+		_, err = client.Product.ReorderOptions(ctx, id, []string{"Color"})
+		assert.EqualError(t, err, "the options Color aren't those of the product")
+	})
+
+	t.Run("reorder values", func(t *testing.T) {
+		gql := optionsGraphQL()
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		err := client.Product.ReorderOptionValues(ctx, id, "Color", []string{"Blue"})
+		require.NoError(t, err)
+		assert.Equal(t, []model.ProductVariantPositionInput{
+			{ID: "gid://shopify/ProductVariant/2", Position: 1},
+			{ID: "gid://shopify/ProductVariant/1", Position: 2},
+			{ID: "gid://shopify/ProductVariant/3", Position: 3},
+		}, gql.variables("productVariantsBulkReorder")[0]["positions"])
+	})
+}
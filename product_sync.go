@@ -0,0 +1,555 @@
+package shopify
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/vinhluan/go-graphql-client"
+	"gopkg.in/guregu/null.v4"
+)
+
+// maxMetafieldsSet is the most metafields a metafieldsSet mutation sets.
+const maxMetafieldsSet = 25
+
+// SyncOption optionally changes how Sync applies the changes.
+type SyncOption func(*syncOptions)
+
+type syncOptions struct {
+	dryRun bool
+}
+
+// WithDryRun plans the changes of Sync and reports them without sending any mutation.
+func WithDryRun() SyncOption {
+	return func(o *syncOptions) {
+		o.dryRun = true
+	}
+}
+
+// SyncReport lists the changes Sync made, or planned in a dry run. Products already in the desired state aren't
+// listed.
+type SyncReport struct {
+	DryRun   bool
+	Products []*ProductSyncChange
+	// Unmatched holds the handles, or IDs, of the desired products no current product matches. Sync doesn't create
+	// products, see Upsert.
+	Unmatched []string
+}
+
+// ProductSyncChange lists the changes of a product.
+type ProductSyncChange struct {
+	ProductID string
+	Handle    string
+	// Fields holds the changed fields of the product, and of its metafields as `metafields.<namespace>.<key>`.
+	Fields            []FieldChange
+	TagsAdded         []string
+	TagsRemoved       []string
+	CollectionsJoined []string
+	CollectionsLeft   []string
+	Variants          []*VariantSyncChange
+	// UnmatchedVariants holds the SKUs, or IDs, of the desired variants no variant of the product matches.
+	UnmatchedVariants []string
+
+	update     *model.ProductInput
+	metafields []model.MetafieldsSetInput
+	variants   []model.ProductVariantsBulkInput
+}
+
+// VariantSyncChange lists the changed fields of a variant.
+type VariantSyncChange struct {
+	VariantID string
+	SKU       string
+	Fields    []FieldChange
+}
+
+// FieldChange is a field changed from its current value to the desired one.
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+type mutationTagsAdd struct {
+	TagsAddResult struct {
+		UserErrors []model.UserError `json:"userErrors,omitempty"`
+	} `graphql:"tagsAdd(id: $id, tags: $tags)" json:"tagsAdd"`
+}
+
+type mutationTagsRemove struct {
+	TagsRemoveResult struct {
+		UserErrors []model.UserError `json:"userErrors,omitempty"`
+	} `graphql:"tagsRemove(id: $id, tags: $tags)" json:"tagsRemove"`
+}
+
+type mutationMetafieldsSet struct {
+	MetafieldsSetResult struct {
+		UserErrors []model.UserError `json:"userErrors,omitempty"`
+	} `graphql:"metafieldsSet(metafields: $metafields)" json:"metafieldsSet"`
+}
+
+// productSyncBulkQuery lists the products with the fields Sync compares.
+var productSyncBulkQuery = fmt.Sprintf(`
+	{
+		products{
+			edges{
+				node{
+					%s
+					status
+					metafields{
+						edges{
+							node{
+								id
+								namespace
+								key
+								value
+								type
+							}
+						}
+					}
+					variants{
+						edges{
+							node{
+								id
+								sku
+								barcode
+								compareAtPrice
+								price
+								taxable
+								inventoryPolicy
+								selectedOptions{
+									name
+									value
+								}
+							}
+						}
+					}
+					collections{
+						edges{
+							node{
+								id
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+`, productBaseQuery)
+
+// Sync brings the products to the desired state, matching them by ID or else by handle, and sends only the
+// mutations needed for the fields that differ. The nil fields of the desired products, and the tags, metafields
+// and variants left out of them, are left as they are, and so are the collections that are neither in
+// CollectionsToJoin nor in CollectionsToLeave. The desired variants are matched by ID or else by SKU.
+func (s *ProductServiceOp) Sync(ctx context.Context, products []model.ProductInput, opts ...SyncOption) (*SyncReport, error) {
+	current := []*model.Product{}
+	err := s.client.BulkOperation.BulkQuery(ctx, productSyncBulkQuery, &current)
+	if err != nil {
+		return nil, fmt.Errorf("list products: %w", err)
+	}
+
+	return s.sync(ctx, products, current, opts...)
+}
+
+func (s *ProductServiceOp) sync(ctx context.Context, products []model.ProductInput, current []*model.Product, opts ...SyncOption) (*SyncReport, error) {
+	options := &syncOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	report, err := planSync(products, current)
+	if err != nil {
+		return nil, err
+	}
+	report.DryRun = options.dryRun
+	if options.dryRun {
+		return report, nil
+	}
+
+	for _, change := range report.Products {
+		err = s.applySyncChange(ctx, change)
+		if err != nil {
+			return report, fmt.Errorf("sync product %s: %w", change.ProductID, err)
+		}
+	}
+
+	return report, nil
+}
+
+func (s *ProductServiceOp) applySyncChange(ctx context.Context, change *ProductSyncChange) error {
+	if change.update != nil {
+		_, err := s.Update(ctx, *change.update)
+		if err != nil {
+			return fmt.Errorf("update: %w", err)
+		}
+	}
+	if len(change.TagsAdded) > 0 {
+		err := s.tagsAdd(ctx, change.ProductID, change.TagsAdded)
+		if err != nil {
+			return fmt.Errorf("add tags: %w", err)
+		}
+	}
+	if len(change.TagsRemoved) > 0 {
+		err := s.tagsRemove(ctx, change.ProductID, change.TagsRemoved)
+		if err != nil {
+			return fmt.Errorf("remove tags: %w", err)
+		}
+	}
+	for i := 0; i < len(change.metafields); i += maxMetafieldsSet {
+		end := i + maxMetafieldsSet
+		if end > len(change.metafields) {
+			end = len(change.metafields)
+		}
+		err := s.metafieldsSet(ctx, change.metafields[i:end])
+		if err != nil {
+			return fmt.Errorf("set metafields: %w", err)
+		}
+	}
+	if len(change.variants) > 0 {
+		err := s.VariantsBulkUpdate(ctx, change.ProductID, change.variants)
+		if err != nil {
+			return fmt.Errorf("update variants: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *ProductServiceOp) tagsAdd(ctx context.Context, id string, tags []string) error {
+	m := mutationTagsAdd{}
+
+	vars := map[string]interface{}{
+		"id":   graphql.ID(id),
+		"tags": tags,
+	}
+	err := s.client.Mutate(ctx, &m, vars)
+	if err != nil {
+		return fmt.Errorf("mutation: %w", err)
+	}
+
+	if len(m.TagsAddResult.UserErrors) > 0 {
+		return fmt.Errorf("%+v", m.TagsAddResult.UserErrors)
+	}
+
+	return nil
+}
+
+func (s *ProductServiceOp) tagsRemove(ctx context.Context, id string, tags []string) error {
+	m := mutationTagsRemove{}
+
+	vars := map[string]interface{}{
+		"id":   graphql.ID(id),
+		"tags": tags,
+	}
+	err := s.client.Mutate(ctx, &m, vars)
+	if err != nil {
+		return fmt.Errorf("mutation: %w", err)
+	}
+
+	if len(m.TagsRemoveResult.UserErrors) > 0 {
+		return fmt.Errorf("%+v", m.TagsRemoveResult.UserErrors)
+	}
+
+	return nil
+}
+
+func (s *ProductServiceOp) metafieldsSet(ctx context.Context, metafields []model.MetafieldsSetInput) error {
+	m := mutationMetafieldsSet{}
+
+	vars := map[string]interface{}{
+		"metafields": metafields,
+	}
+	err := s.client.Mutate(ctx, &m, vars)
+	if err != nil {
+		return fmt.Errorf("mutation: %w", err)
+	}
+
+	if len(m.MetafieldsSetResult.UserErrors) > 0 {
+		return fmt.Errorf("%+v", m.MetafieldsSetResult.UserErrors)
+	}
+
+	return nil
+}
+
+// planSync compares the desired products with the current ones and plans the changes.
+func planSync(products []model.ProductInput, current []*model.Product) (*SyncReport, error) {
+	byID := make(map[string]*model.Product, len(current))
+	byHandle := make(map[string]*model.Product, len(current))
+	for _, p := range current {
+		byID[p.ID] = p
+		byHandle[p.Handle] = p
+	}
+
+	report := &SyncReport{}
+	for _, desired := range products {
+		var p *model.Product
+		var key string
+		switch {
+		case desired.ID != nil:
+			key = *desired.ID
+			p = byID[key]
+		case desired.Handle != nil:
+			key = *desired.Handle
+			p = byHandle[key]
+		default:
+			return nil, fmt.Errorf("every product must have an ID or a handle to be matched")
+		}
+		if p == nil {
+			report.Unmatched = append(report.Unmatched, key)
+			continue
+		}
+
+		change, err := diffProduct(desired, p)
+		if err != nil {
+			return nil, fmt.Errorf("product %s: %w", key, err)
+		}
+		if change != nil {
+			report.Products = append(report.Products, change)
+		}
+	}
+
+	return report, nil
+}
+
+// diffProduct returns the changes bringing the product p to the desired state, or nil when there are none.
+func diffProduct(desired model.ProductInput, p *model.Product) (*ProductSyncChange, error) {
+	change := &ProductSyncChange{ProductID: p.ID, Handle: p.Handle}
+	update := model.ProductInput{ID: &p.ID}
+	updated := false
+
+	diffString := func(field string, to *string, from string, set func()) {
+		if to != nil && *to != from {
+			change.Fields = append(change.Fields, FieldChange{Field: field, From: from, To: *to})
+			set()
+			updated = true
+		}
+	}
+	diffString("title", desired.Title, p.Title, func() { update.Title = desired.Title })
+	diffString("handle", desired.Handle, p.Handle, func() { update.Handle = desired.Handle })
+	diffString("descriptionHtml", desired.DescriptionHTML, p.DescriptionHTML, func() { update.DescriptionHTML = desired.DescriptionHTML })
+	diffString("productType", desired.ProductType, p.ProductType, func() { update.ProductType = desired.ProductType })
+	diffString("vendor", desired.Vendor, p.Vendor, func() { update.Vendor = desired.Vendor })
+	diffString("templateSuffix", desired.TemplateSuffix, stringValue(p.TemplateSuffix), func() { update.TemplateSuffix = desired.TemplateSuffix })
+	diffString("customProductType", desired.CustomProductType, stringValue(p.CustomProductType), func() { update.CustomProductType = desired.CustomProductType })
+	if desired.Status != nil && *desired.Status != p.Status {
+		change.Fields = append(change.Fields, FieldChange{Field: "status", From: p.Status.String(), To: desired.Status.String()})
+		update.Status = desired.Status
+		updated = true
+	}
+	if desired.Seo != nil {
+		seo := p.Seo
+		if seo == nil {
+			seo = &model.Seo{}
+		}
+		diffString("seo.title", desired.Seo.Title, stringValue(seo.Title), func() { update.Seo = desired.Seo })
+		diffString("seo.description", desired.Seo.Description, stringValue(seo.Description), func() { update.Seo = desired.Seo })
+	}
+
+	collections := map[string]bool{}
+	if p.Collections != nil {
+		for _, edge := range p.Collections.Edges {
+			if edge.Node != nil {
+				collections[edge.Node.ID] = true
+			}
+		}
+	}
+	for _, id := range desired.CollectionsToJoin {
+		if !collections[id] {
+			change.CollectionsJoined = append(change.CollectionsJoined, id)
+		}
+	}
+	for _, id := range desired.CollectionsToLeave {
+		if collections[id] {
+			change.CollectionsLeft = append(change.CollectionsLeft, id)
+		}
+	}
+	if len(change.CollectionsJoined) > 0 || len(change.CollectionsLeft) > 0 {
+		update.CollectionsToJoin = change.CollectionsJoined
+		update.CollectionsToLeave = change.CollectionsLeft
+		updated = true
+	}
+	if updated {
+		change.update = &update
+	}
+
+	if desired.Tags != nil {
+		change.TagsAdded, change.TagsRemoved = diffTags(desired.Tags, p.Tags)
+	}
+
+	err := diffMetafields(change, desired.Metafields, p)
+	if err != nil {
+		return nil, err
+	}
+	diffVariants(change, desired.Variants, p)
+
+	if change.update == nil && len(change.TagsAdded) == 0 && len(change.TagsRemoved) == 0 &&
+		len(change.metafields) == 0 && len(change.variants) == 0 && len(change.UnmatchedVariants) == 0 {
+		return nil, nil
+	}
+	return change, nil
+}
+
+// diffTags returns the tags to add and to remove, compared without regard to case as Shopify does.
+func diffTags(desired, current []string) (added, removed []string) {
+	has := make(map[string]bool, len(current))
+	for _, tag := range current {
+		has[strings.ToLower(tag)] = true
+	}
+	wanted := make(map[string]bool, len(desired))
+	for _, tag := range desired {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || wanted[strings.ToLower(tag)] {
+			continue
+		}
+		wanted[strings.ToLower(tag)] = true
+		if !has[strings.ToLower(tag)] {
+			added = append(added, tag)
+		}
+	}
+	for _, tag := range current {
+		if !wanted[strings.ToLower(tag)] {
+			removed = append(removed, tag)
+		}
+	}
+	return added, removed
+}
+
+func diffMetafields(change *ProductSyncChange, desired []model.MetafieldInput, p *model.Product) error {
+	current := map[string]*model.Metafield{}
+	if p.Metafields != nil {
+		for _, edge := range p.Metafields.Edges {
+			if edge.Node != nil {
+				current[edge.Node.Namespace+"."+edge.Node.Key] = edge.Node
+			}
+		}
+	}
+
+	for _, m := range desired {
+		if m.Namespace == nil || m.Key == nil || m.Value == nil {
+			return fmt.Errorf("every metafield must have a namespace, a key and a value")
+		}
+		name := *m.Namespace + "." + *m.Key
+		set := model.MetafieldsSetInput{OwnerID: p.ID, Namespace: *m.Namespace, Key: *m.Key, Value: *m.Value, Type: m.Type}
+		from := ""
+		if c, ok := current[name]; ok {
+			if c.Value == *m.Value && (m.Type == nil || *m.Type == c.Type) {
+				continue
+			}
+			from = c.Value
+			if set.Type == nil {
+				set.Type = &c.Type
+			}
+		}
+		change.Fields = append(change.Fields, FieldChange{Field: "metafields." + name, From: from, To: *m.Value})
+		change.metafields = append(change.metafields, set)
+	}
+	return nil
+}
+
+func diffVariants(change *ProductSyncChange, desired []model.ProductVariantInput, p *model.Product) {
+	byID := map[string]*model.ProductVariant{}
+	bySKU := map[string]*model.ProductVariant{}
+	if p.Variants != nil {
+		for _, edge := range p.Variants.Edges {
+			if edge.Node == nil {
+				continue
+			}
+			byID[edge.Node.ID] = edge.Node
+			if edge.Node.Sku != nil && *edge.Node.Sku != "" {
+				bySKU[*edge.Node.Sku] = edge.Node
+			}
+		}
+	}
+
+	for _, d := range desired {
+		var v *model.ProductVariant
+		switch {
+		case d.ID != nil:
+			v = byID[*d.ID]
+		case d.Sku != nil:
+			v = bySKU[*d.Sku]
+		}
+		if v == nil {
+			change.UnmatchedVariants = append(change.UnmatchedVariants, variantKey(d))
+			continue
+		}
+
+		vc := &VariantSyncChange{VariantID: v.ID, SKU: stringValue(v.Sku)}
+		input := model.ProductVariantsBulkInput{ID: &v.ID}
+		diff := func(field, from, to string, set func()) {
+			vc.Fields = append(vc.Fields, FieldChange{Field: field, From: from, To: to})
+			set()
+		}
+		if d.Sku != nil && *d.Sku != stringValue(v.Sku) {
+			diff("sku", stringValue(v.Sku), *d.Sku, func() { input.Sku = d.Sku })
+		}
+		if d.Price != nil && !decimalEqual(d.Price.String, v.Price.String) {
+			diff("price", v.Price.String, d.Price.String, func() { input.Price = d.Price })
+		}
+		if d.CompareAtPrice != nil && !nullDecimalEqual(*d.CompareAtPrice, v.CompareAtPrice) {
+			diff("compareAtPrice", nullStringValue(v.CompareAtPrice), d.CompareAtPrice.String, func() { input.CompareAtPrice = d.CompareAtPrice })
+		}
+		if d.Barcode != nil && *d.Barcode != stringValue(v.Barcode) {
+			diff("barcode", stringValue(v.Barcode), *d.Barcode, func() { input.Barcode = d.Barcode })
+		}
+		if d.Taxable != nil && *d.Taxable != v.Taxable {
+			diff("taxable", strconv.FormatBool(v.Taxable), strconv.FormatBool(*d.Taxable), func() { input.Taxable = d.Taxable })
+		}
+		if d.InventoryPolicy != nil && *d.InventoryPolicy != v.InventoryPolicy {
+			diff("inventoryPolicy", v.InventoryPolicy.String(), d.InventoryPolicy.String(), func() { input.InventoryPolicy = d.InventoryPolicy })
+		}
+		if d.Options != nil {
+			options := make([]string, len(v.SelectedOptions))
+			for i, o := range v.SelectedOptions {
+				options[i] = o.Value
+			}
+			if strings.Join(options, "\x00") != strings.Join(d.Options, "\x00") {
+				diff("options", strings.Join(options, " / "), strings.Join(d.Options, " / "), func() { input.Options = d.Options })
+			}
+		}
+
+		if len(vc.Fields) > 0 {
+			change.Variants = append(change.Variants, vc)
+			change.variants = append(change.variants, input)
+		}
+	}
+}
+
+func variantKey(v model.ProductVariantInput) string {
+	if v.ID != nil {
+		return *v.ID
+	}
+	return stringValue(v.Sku)
+}
+
+// decimalEqual compares the amounts as numbers, so `10` equals the `10.00` Shopify returns.
+func decimalEqual(a, b string) bool {
+	x, okX := new(big.Rat).SetString(a)
+	y, okY := new(big.Rat).SetString(b)
+	if !okX || !okY {
+		return a == b
+	}
+	return x.Cmp(y) == 0
+}
+
+func nullDecimalEqual(desired null.String, current *null.String) bool {
+	if current == nil || !current.Valid {
+		return !desired.Valid || desired.String == ""
+	}
+	return desired.Valid && decimalEqual(desired.String, current.String)
+}
+
+func nullStringValue(s *null.String) string {
+	if s == nil {
+		return ""
+	}
+	return s.String
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,224 @@
+package shopify
+
+import (
+	"context"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/sogko/go-shopify-graphql/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vinhluan/go-graphql-client"
+	"gopkg.in/guregu/null.v4"
+)
+
+// syncGraphQL answers the product updates of Sync with the product of syncProducts.
+func syncGraphQL() *queuedGraphQL {
+	return &queuedGraphQL{responses: map[string][]string{"productUpdate": {`{"product":{"id":"gid://shopify/Product/1"}}`}}}
+}
+
+// syncProducts returns the current products, decoded from a result holding only the fields productSyncBulkQuery
+// selects.
+func syncProducts(t *testing.T) []*model.Product {
+	fixture := `[{
+		"id": "gid://shopify/Product/1",
+		"handle": "shirt",
+		"title": "Shirt",
+		"vendor": "Acme",
+		"status": "ACTIVE",
+		"tags": ["Cotton", "summer"],
+		"metafields": {"edges": [
+			{"node": {"namespace": "custom", "key": "fabric", "value": "cotton", "type": "single_line_text_field"}}
+		]},
+		"variants": {"edges": [
+			{"node": {"id": "gid://shopify/ProductVariant/1", "sku": "S-1", "price": "10.00", "taxable": true, "inventoryPolicy": "DENY",
+				"selectedOptions": [{"name": "Size", "value": "S"}]}},
+			{"node": {"id": "gid://shopify/ProductVariant/2", "sku": "S-2", "price": "12.00", "taxable": true, "inventoryPolicy": "DENY",
+				"selectedOptions": [{"name": "Size", "value": "M"}]}}
+		]},
+		"collections": {"edges": [{"node": {"id": "gid://shopify/Collection/1"}}]}
+	}]`
+
+	doc, err := schema.ParseQuery(productSyncBulkQuery)
+	require.NoError(t, err)
+	node := doc.Operations[0].SelectionSet[0].(*schema.Field).SelectionSet[0].(*schema.Field).SelectionSet[0].(*schema.Field)
+	var result interface{}
+	require.NoError(t, jsoniter.UnmarshalFromString(fixture, &result))
+	requireSelected(t, node.SelectionSet, result, "products")
+
+	products := []*model.Product{}
+	require.NoError(t, jsoniter.UnmarshalFromString(fixture, &products))
+	return products
+}
+
+// requireSelected fails when the decoded JSON v holds a field the selection set doesn't select.
+func requireSelected(t *testing.T, set schema.SelectionSet, v interface{}, path string) {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, item := range v {
+			requireSelected(t, set, item, path)
+		}
+	case map[string]interface{}:
+		for key, value := range v {
+			var field *schema.Field
+			for _, sel := range set {
+				if f, ok := sel.(*schema.Field); ok && f.ResponseKey() == key {
+					field = f
+				}
+			}
+			require.NotNil(t, field, "%s.%s isn't selected by the query", path, key)
+			requireSelected(t, field.SelectionSet, value, path+"."+key)
+		}
+	}
+}
+
+func TestPlanSync(t *testing.T) {
+	current := syncProducts(t)
+	handle := "shirt"
+	title := "Shirt"
+	vendor := "Acme Inc"
+	namespace, fabric, linen, origin, portugal := "custom", "fabric", "linen", "origin", "Portugal"
+	sku := "S-1"
+	price, samePrice := null.StringFrom("11"), null.StringFrom("12")
+	options := []string{"L"}
+	missing := "gone"
+
+	t.Run("changes", func(t *testing.T) {
+		report, err := planSync([]model.ProductInput{{
+			Handle:             &handle,
+			Title:              &title,
+			Vendor:             &vendor,
+			Tags:               []string{"cotton", "Linen"},
+			CollectionsToJoin:  []string{"gid://shopify/Collection/1", "gid://shopify/Collection/2"},
+			CollectionsToLeave: []string{"gid://shopify/Collection/3"},
+			Metafields: []model.MetafieldInput{
+				{Namespace: &namespace, Key: &fabric, Value: &linen},
+				{Namespace: &namespace, Key: &origin, Value: &portugal},
+			},
+			Variants: []model.ProductVariantInput{
+				{Sku: &sku, Price: &price},
+				{ID: &current[0].Variants.Edges[1].Node.ID, Price: &samePrice, Options: options},
+				{Sku: &missing},
+			},
+		}}, current)
+		require.NoError(t, err)
+		assert.Empty(t, report.Unmatched)
+		require.Len(t, report.Products, 1)
+
+		change := report.Products[0]
+		assert.Equal(t, "gid://shopify/Product/1", change.ProductID)
+		assert.Equal(t, []FieldChange{
+			{Field: "vendor", From: "Acme", To: "Acme Inc"},
+			{Field: "metafields.custom.fabric", From: "cotton", To: "linen"},
+			{Field: "metafields.custom.origin", From: "", To: "Portugal"},
+		}, change.Fields)
+		assert.Equal(t, []string{"Linen"}, change.TagsAdded)
+		assert.Equal(t, []string{"summer"}, change.TagsRemoved)
+		assert.Equal(t, []string{"gid://shopify/Collection/2"}, change.CollectionsJoined)
+		assert.Empty(t, change.CollectionsLeft)
+		assert.Equal(t, []string{"gone"}, change.UnmatchedVariants)
+		assert.Equal(t, []*VariantSyncChange{
+			{VariantID: "gid://shopify/ProductVariant/1", SKU: "S-1", Fields: []FieldChange{{Field: "price", From: "10.00", To: "11"}}},
+			{VariantID: "gid://shopify/ProductVariant/2", SKU: "S-2", Fields: []FieldChange{{Field: "options", From: "M", To: "L"}}},
+		}, change.Variants)
+
+		require.NotNil(t, change.update)
+		assert.Equal(t, "gid://shopify/Product/1", *change.update.ID)
+		assert.Nil(t, change.update.Title)
+		assert.Equal(t, "Acme Inc", *change.update.Vendor)
+		assert.Equal(t, []string{"gid://shopify/Collection/2"}, change.update.CollectionsToJoin)
+		assert.Nil(t, change.update.Tags)
+		require.Len(t, change.metafields, 2)
+		assert.Equal(t, "single_line_text_field", *change.metafields[0].Type)
+		assert.Nil(t, change.metafields[1].Type)
+		require.Len(t, change.variants, 2)
+		assert.Nil(t, change.variants[0].Sku)
+		assert.Nil(t, change.variants[1].Price)
+	})
+
+	t.Run("in sync", func(t *testing.T) {
+		samePrice := null.StringFrom("10")
+		report, err := planSync([]model.ProductInput{{
+			ID:       &current[0].ID,
+			Title:    &title,
+			Tags:     []string{"summer", "COTTON"},
+			Variants: []model.ProductVariantInput{{Sku: &sku, Price: &samePrice}},
+		}}, current)
+		require.NoError(t, err)
+		assert.Empty(t, report.Products)
+	})
+
+	t.Run("unmatched", func(t *testing.T) {
+		other := "pants"
+		report, err := planSync([]model.ProductInput{{Handle: &other}}, current)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"pants"}, report.Unmatched)
+
+		_, err = planSync([]model.ProductInput{{Title: &title}}, current)
+		assert.EqualError(t, err, "every product must have an ID or a handle to be matched")
+	})
+}
+
+func TestProductSync(t *testing.T) {
+	handle := "shirt"
+	vendor := "Acme Inc"
+	namespace, key, value := "custom", "fabric", "linen"
+	sku := "S-1"
+	price := null.StringFrom("11")
+	desired := []model.ProductInput{{
+		Handle:     &handle,
+		Vendor:     &vendor,
+		Tags:       []string{"Cotton", "linen"},
+		Metafields: []model.MetafieldInput{{Namespace: &namespace, Key: &key, Value: &value}},
+		Variants:   []model.ProductVariantInput{{Sku: &sku, Price: &price}},
+	}}
+
+	t.Run("apply", func(t *testing.T) {
+		gql := syncGraphQL()
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		report, err := client.Product.(*ProductServiceOp).sync(context.Background(), desired, syncProducts(t))
+		require.NoError(t, err)
+		assert.False(t, report.DryRun)
+		assert.Equal(t, []string{"productUpdate", "tagsAdd", "tagsRemove", "metafieldsSet", "productVariantsBulkUpdate"}, gql.mutations())
+		assert.Equal(t, []string{"linen"}, gql.variables("tagsAdd")[0]["tags"])
+		assert.Equal(t, []string{"summer"}, gql.variables("tagsRemove")[0]["tags"])
+		assert.Equal(t, graphql.ID("gid://shopify/Product/1"), gql.variables("tagsRemove")[0]["id"])
+		assert.Equal(t, []model.MetafieldsSetInput{{
+			OwnerID: "gid://shopify/Product/1", Namespace: "custom", Key: "fabric", Value: "linen", Type: report.Products[0].metafields[0].Type,
+		}}, gql.variables("metafieldsSet")[0]["metafields"])
+	})
+
+	t.Run("unchanged variant with options", func(t *testing.T) {
+		gql := syncGraphQL()
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		current := syncProducts(t)
+		samePrice := null.StringFrom("12.00")
+		report, err := client.Product.(*ProductServiceOp).sync(context.Background(), []model.ProductInput{{
+			Handle:   &handle,
+			Variants: []model.ProductVariantInput{{ID: &current[0].Variants.Edges[1].Node.ID, Price: &samePrice, Options: []string{"M"}}},
+		}}, current)
+		require.NoError(t, err)
+		assert.Empty(t, report.Products)
+		assert.Empty(t, gql.mutations())
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		gql := syncGraphQL()
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		report, err := client.Product.(*ProductServiceOp).sync(context.Background(), desired, syncProducts(t), WithDryRun())
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		require.Len(t, report.Products, 1)
+		assert.Empty(t, gql.mutations())
+	})
+}
+
+func TestDiffTags(t *testing.T) {
+	added, removed := diffTags([]string{" New ", "same", "", "new"}, []string{"Same", "old"})
+	assert.Equal(t, []string{"New"}, added)
+	assert.Equal(t, []string{"old"}, removed)
+}
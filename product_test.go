@@ -0,0 +1,120 @@
+package shopify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductListPage(t *testing.T) {
+	gql := &queuedGraphQL{responses: map[string][]string{"products": {
+		`{"edges":[{"node":{"id":"gid://shopify/Product/1","title":"Shirt"}},{"node":{"id":"gid://shopify/Product/2","title":"Hat"}}],"pageInfo":{"hasNextPage":true,"hasPreviousPage":false,"startCursor":"c1","endCursor":"c2"}}`,
+	}}}
+	client := NewClient("test", WithGraphQLClient(gql))
+
+	products, page, err := client.Product.ListPage(context.Background(), ListOptions{
+		Query:   SearchField("status", "active").String(),
+		SortKey: model.ProductSortKeysTitle.String(),
+		Reverse: true,
+		Fields:  "id title",
+	})
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.Equal(t, "Hat", products[1].Title)
+	assert.True(t, page.HasNextPage)
+	assert.False(t, page.HasPreviousPage)
+	assert.Equal(t, "c1", *page.StartCursor)
+	assert.Equal(t, "c2", *page.EndCursor)
+
+	assert.Contains(t, gql.operations[0].document, "id title")
+	assert.Equal(t, map[string]interface{}{
+		"query":   `status:"active"`,
+		"first":   defaultListPageSize,
+		"reverse": true,
+		"sortKey": model.ProductSortKeysTitle,
+	}, gql.variables("products")[0])
+
+	_, _, err = client.Product.ListPage(context.Background(), ListOptions{SortKey: "PRICE"})
+	assert.EqualError(t, err, "invalid product sort key `PRICE`")
+}
+
+func TestListPageVariables(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ListOptions
+		want    map[string]interface{}
+		wantErr string
+	}{{
+		name: "next page",
+		opts: ListOptions{First: 10, After: "c2"},
+		want: map[string]interface{}{"first": 10, "after": "c2", "reverse": false},
+	}, {
+		name: "previous page",
+		opts: ListOptions{Before: "c1"},
+		want: map[string]interface{}{"last": defaultListPageSize, "before": "c1", "reverse": false},
+	}, {
+		name:    "page too large",
+		opts:    ListOptions{First: 251},
+		wantErr: "the page size must be between 1 and 250",
+	}, {
+		name:    "both directions",
+		opts:    ListOptions{First: 10, Last: 10},
+		wantErr: "only one of First and Last can be set",
+	}, {
+		name:    "both cursors",
+		opts:    ListOptions{After: "c2", Before: "c1"},
+		wantErr: "only one of After and Before can be set",
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			vars, err := listPageVariables(tt.opts)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, vars)
+		})
+	}
+}
+
+func TestProductCreateUpdate(t *testing.T) {
+	ctx := context.Background()
+	title := "Shirt"
+
+	t.Run("create", func(t *testing.T) {
+		gql := &queuedGraphQL{responses: map[string][]string{"productCreate": {`{"product":{"id":"gid://shopify/Product/1","title":"Shirt"}}`}}}
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		media := []model.CreateMediaInput{{OriginalSource: "https://example.com/shirt.png", MediaContentType: model.MediaContentTypeImage}}
+		product, err := client.Product.Create(ctx, model.ProductInput{Title: &title}, media)
+		require.NoError(t, err)
+		assert.Equal(t, "Shirt", product.Title)
+		assert.Equal(t, productCreateMutation, gql.operations[0].document)
+		assert.Equal(t, model.ProductInput{Title: &title}, gql.operations[0].variables["input"])
+		assert.Equal(t, media, gql.operations[0].variables["media"])
+	})
+
+	t.Run("update", func(t *testing.T) {
+		gql := &queuedGraphQL{responses: map[string][]string{"productUpdate": {`{"product":{"id":"gid://shopify/Product/1","title":"Shirt"}}`}}}
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		id := "gid://shopify/Product/1"
+		product, err := client.Product.Update(ctx, model.ProductInput{ID: &id, Title: &title})
+		require.NoError(t, err)
+		assert.Equal(t, "gid://shopify/Product/1", product.ID)
+		assert.Equal(t, productUpdateMutation, gql.operations[0].document)
+	})
+
+	t.Run("user errors", func(t *testing.T) {
+		gql := &queuedGraphQL{responses: map[string][]string{"productUpdate": {`{"userErrors":[{"field":["title"],"message":"Title can't be blank"}]}`}}}
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		_, err := client.Product.Update(ctx, model.ProductInput{})
+		assert.ErrorContains(t, err, "Title can't be blank")
+	})
+}
@@ -0,0 +1,317 @@
+package shopify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sogko/go-shopify-graphql/model"
+)
+
+// ProductUpsertKey identifies the existing product Upsert updates. Either Handle or SKU is set. A metafield
+// identifier isn't supported, the bundled API version can't look a product up by metafield value.
+type ProductUpsertKey struct {
+	Handle string
+	// SKU matches the product of the variant with the SKU.
+	SKU string
+}
+
+// ProductUpsert is the desired state of a product.
+type ProductUpsert struct {
+	// Product holds the fields of the product, its ID is set by Upsert. Its Variants must be left empty, the variants
+	// are set in Variants.
+	Product model.ProductInput
+	// Variants are the desired variants, matched to the existing ones by SKU. Every variant must have a unique SKU,
+	// the existing variants without a match are deleted. Without Variants the existing variants are left as they are.
+	Variants []model.ProductVariantsBulkInput
+}
+
+// UpsertAction is the action Upsert took.
+type UpsertAction string
+
+const (
+	UpsertActionCreated UpsertAction = "CREATED"
+	UpsertActionUpdated UpsertAction = "UPDATED"
+)
+
+// ProductUpsertResult reports the changes Upsert made to the product.
+type ProductUpsertResult struct {
+	Action  UpsertAction
+	Product *model.Product
+	// VariantsCreated and VariantsUpdated hold the SKUs of the variants.
+	VariantsCreated []string
+	VariantsUpdated []string
+	// VariantsDeleted holds the IDs of the variants.
+	VariantsDeleted []string
+}
+
+// productUpsertTarget is the existing product of an upsert with its variants.
+type productUpsertTarget struct {
+	ID       string `json:"id"`
+	Variants struct {
+		Edges []struct {
+			Node struct {
+				ID  string `json:"id"`
+				SKU string `json:"sku"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"variants"`
+}
+
+const productUpsertTargetQuery = `
+	id
+	variants(first:250){
+		edges{
+			node{
+				id
+				sku
+			}
+		}
+	}
+`
+
+// Upsert creates the product when no product matches the key, or else updates the product and its variants.
+// The bundled API version has no `productSet` mutation, so the product and its variants are written with the
+// product and bulk variant mutations.
+func (s *ProductServiceOp) Upsert(ctx context.Context, key ProductUpsertKey, product ProductUpsert) (*ProductUpsertResult, error) {
+	if len(product.Product.Variants) > 0 {
+		return nil, fmt.Errorf("the variants must be set in ProductUpsert.Variants, not in Product.Variants")
+	}
+	skus := map[string]bool{}
+	for _, v := range product.Variants {
+		if v.Sku == nil || *v.Sku == "" {
+			return nil, fmt.Errorf("every variant must have a SKU to be matched")
+		}
+		if skus[*v.Sku] {
+			return nil, fmt.Errorf("duplicate variant SKU `%s`", *v.Sku)
+		}
+		skus[*v.Sku] = true
+	}
+
+	target, err := s.findUpsertTarget(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return s.upsertCreate(ctx, product)
+	}
+	return s.upsertUpdate(ctx, target, product)
+}
+
+func (s *ProductServiceOp) findUpsertTarget(ctx context.Context, key ProductUpsertKey) (*productUpsertTarget, error) {
+	switch {
+	case key.Handle != "" && key.SKU != "":
+		return nil, fmt.Errorf("only one of Handle and SKU can be set")
+	case key.Handle != "":
+		q := fmt.Sprintf(`
+			query productByHandle($handle: String!) {
+				productByHandle(handle: $handle){
+					%s
+				}
+			}
+		`, productUpsertTargetQuery)
+
+		out := struct {
+			Product *productUpsertTarget `json:"productByHandle"`
+		}{}
+		err := s.client.QueryString(ctx, q, map[string]interface{}{"handle": key.Handle}, &out)
+		if err != nil {
+			return nil, fmt.Errorf("query: %w", err)
+		}
+		return out.Product, nil
+	case key.SKU != "":
+		q := fmt.Sprintf(`
+			query productVariants($query: String!, $cursor: String) {
+				productVariants(first:%d, query: $query, after: $cursor){
+					edges{
+						node{
+							sku
+							product{
+								id
+							}
+						}
+					}
+					pageInfo{
+						hasNextPage
+						endCursor
+					}
+				}
+			}
+		`, variantLookupPageSize)
+
+		vars := map[string]interface{}{"query": SearchField("sku", key.SKU).String()}
+
+		// The search matches the SKU loosely, so the variants of every page are checked for the exact SKU.
+		var productID string
+		for {
+			out := struct {
+				ProductVariants struct {
+					Edges []struct {
+						Node struct {
+							SKU     string `json:"sku"`
+							Product *struct {
+								ID string `json:"id"`
+							} `json:"product"`
+						} `json:"node"`
+					} `json:"edges"`
+					PageInfo *model.PageInfo `json:"pageInfo"`
+				} `json:"productVariants"`
+			}{}
+			err := s.client.QueryString(ctx, q, vars, &out)
+			if err != nil {
+				return nil, fmt.Errorf("query: %w", err)
+			}
+
+			for _, edge := range out.ProductVariants.Edges {
+				p := edge.Node.Product
+				if p == nil || edge.Node.SKU != key.SKU {
+					continue
+				}
+				if productID != "" && productID != p.ID {
+					return nil, fmt.Errorf("the SKU `%s` matches several products", key.SKU)
+				}
+				productID = p.ID
+			}
+
+			cursor, ok := nextPageCursor(out.ProductVariants.PageInfo)
+			if !ok {
+				break
+			}
+			vars["cursor"] = cursor
+		}
+		if productID == "" {
+			return nil, nil
+		}
+
+		q = fmt.Sprintf(`
+			query product($id: ID!) {
+				product(id: $id){
+					%s
+				}
+			}
+		`, productUpsertTargetQuery)
+
+		out := struct {
+			Product *productUpsertTarget `json:"product"`
+		}{}
+		err := s.client.QueryString(ctx, q, map[string]interface{}{"id": productID}, &out)
+		if err != nil {
+			return nil, fmt.Errorf("query: %w", err)
+		}
+		return out.Product, nil
+	default:
+		return nil, fmt.Errorf("either Handle or SKU must be set")
+	}
+}
+
+func (s *ProductServiceOp) upsertCreate(ctx context.Context, product ProductUpsert) (*ProductUpsertResult, error) {
+	input := product.Product
+	input.ID = nil
+	input.Variants = make([]model.ProductVariantInput, len(product.Variants))
+	res := &ProductUpsertResult{Action: UpsertActionCreated}
+	for i, v := range product.Variants {
+		input.Variants[i] = productVariantInput(v)
+		res.VariantsCreated = append(res.VariantsCreated, *v.Sku)
+	}
+
+	created, err := s.Create(ctx, input, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create product: %w", err)
+	}
+	res.Product = created
+
+	return res, nil
+}
+
+// upsertUpdate updates the product and its variants. The unmatched variants are deleted before the new ones are
+// created, so a new variant can take the options of a deleted one, unless that would delete every variant.
+func (s *ProductServiceOp) upsertUpdate(ctx context.Context, target *productUpsertTarget, product ProductUpsert) (*ProductUpsertResult, error) {
+	input := product.Product
+	input.ID = &target.ID
+	input.Variants = nil
+	updated, err := s.Update(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("update product: %w", err)
+	}
+	res := &ProductUpsertResult{Action: UpsertActionUpdated, Product: updated}
+	if len(product.Variants) == 0 {
+		return res, nil
+	}
+
+	existing := map[string]string{}
+	for _, edge := range target.Variants.Edges {
+		if edge.Node.SKU != "" {
+			existing[edge.Node.SKU] = edge.Node.ID
+		}
+	}
+	var toCreate, toUpdate []model.ProductVariantsBulkInput
+	desired := map[string]bool{}
+	for _, v := range product.Variants {
+		desired[*v.Sku] = true
+		if id, ok := existing[*v.Sku]; ok {
+			v.ID = &id
+			toUpdate = append(toUpdate, v)
+			res.VariantsUpdated = append(res.VariantsUpdated, *v.Sku)
+			continue
+		}
+		v.ID = nil
+		toCreate = append(toCreate, v)
+		res.VariantsCreated = append(res.VariantsCreated, *v.Sku)
+	}
+	for _, edge := range target.Variants.Edges {
+		if edge.Node.SKU == "" || !desired[edge.Node.SKU] {
+			res.VariantsDeleted = append(res.VariantsDeleted, edge.Node.ID)
+		}
+	}
+
+	if len(toUpdate) > 0 {
+		err = s.VariantsBulkUpdate(ctx, target.ID, toUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("update variants: %w", err)
+		}
+	}
+
+	deleteFirst := len(res.VariantsDeleted) < len(target.Variants.Edges)
+	if deleteFirst && len(res.VariantsDeleted) > 0 {
+		err = s.VariantsBulkDelete(ctx, target.ID, res.VariantsDeleted)
+		if err != nil {
+			return nil, fmt.Errorf("delete variants: %w", err)
+		}
+	}
+	if len(toCreate) > 0 {
+		err = s.VariantsBulkCreate(ctx, target.ID, toCreate)
+		if err != nil {
+			return nil, fmt.Errorf("create variants: %w", err)
+		}
+	}
+	if !deleteFirst && len(res.VariantsDeleted) > 0 {
+		err = s.VariantsBulkDelete(ctx, target.ID, res.VariantsDeleted)
+		if err != nil {
+			return nil, fmt.Errorf("delete variants: %w", err)
+		}
+	}
+
+	return res, nil
+}
+
+// productVariantInput converts the input of a bulk variant mutation to the input of a variant of productCreate.
+func productVariantInput(v model.ProductVariantsBulkInput) model.ProductVariantInput {
+	return model.ProductVariantInput{
+		Barcode:              v.Barcode,
+		CompareAtPrice:       v.CompareAtPrice,
+		HarmonizedSystemCode: v.HarmonizedSystemCode,
+		MediaID:              v.MediaID,
+		MediaSrc:             v.MediaSrc,
+		InventoryPolicy:      v.InventoryPolicy,
+		InventoryQuantities:  v.InventoryQuantities,
+		InventoryItem:        v.InventoryItem,
+		Metafields:           v.Metafields,
+		Options:              v.Options,
+		Price:                v.Price,
+		RequiresShipping:     v.RequiresShipping,
+		Sku:                  v.Sku,
+		Taxable:              v.Taxable,
+		TaxCode:              v.TaxCode,
+		Weight:               v.Weight,
+		WeightUnit:           v.WeightUnit,
+	}
+}
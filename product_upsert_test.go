@@ -0,0 +1,155 @@
+package shopify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vinhluan/go-graphql-client"
+	"gopkg.in/guregu/null.v4"
+)
+
+// upsertGraphQL answers the product lookups of Upsert with lookups, and its product mutations with the product 2
+// when it's created or 1 when it's updated.
+func upsertGraphQL(lookups map[string][]string) *queuedGraphQL {
+	responses := map[string][]string{
+		"productCreate": {`{"product":{"id":"gid://shopify/Product/2"}}`},
+		"productUpdate": {`{"product":{"id":"gid://shopify/Product/1"}}`},
+	}
+	for field, lookup := range lookups {
+		responses[field] = lookup
+	}
+	return &queuedGraphQL{responses: responses}
+}
+
+func upsertVariant(sku, price string) model.ProductVariantsBulkInput {
+	p := null.StringFrom(price)
+	return model.ProductVariantsBulkInput{Sku: &sku, Price: &p}
+}
+
+func TestProductUpsert(t *testing.T) {
+	ctx := context.Background()
+	title := "Shirt"
+
+	t.Run("create", func(t *testing.T) {
+		gql := upsertGraphQL(map[string][]string{"productByHandle": {`null`}})
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		res, err := client.Product.Upsert(ctx, ProductUpsertKey{Handle: "shirt"}, ProductUpsert{
+			Product:  model.ProductInput{Title: &title},
+			Variants: []model.ProductVariantsBulkInput{upsertVariant("S-1", "10.00"), upsertVariant("S-2", "12.00")},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, UpsertActionCreated, res.Action)
+		assert.Equal(t, "gid://shopify/Product/2", res.Product.ID)
+		assert.Equal(t, []string{"S-1", "S-2"}, res.VariantsCreated)
+		assert.Equal(t, []string{"productCreate"}, gql.mutations())
+		assert.Equal(t, "shirt", gql.variables("productByHandle")[0]["handle"])
+		input := gql.variables("productCreate")[0]["input"].(model.ProductInput)
+		require.Len(t, input.Variants, 2)
+		assert.Equal(t, "12.00", input.Variants[1].Price.String)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		gql := upsertGraphQL(map[string][]string{
+			"productVariants": {
+				`{"edges":[{"node":{"sku":"S-10","product":{"id":"gid://shopify/Product/3"}}}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}`,
+				`{"edges":[{"node":{"sku":"S-1","product":{"id":"gid://shopify/Product/1"}}}]}`,
+			},
+			"product": {`{"id":"gid://shopify/Product/1","variants":{"edges":[
+				{"node":{"id":"gid://shopify/ProductVariant/1","sku":"S-1"}},
+				{"node":{"id":"gid://shopify/ProductVariant/2","sku":"S-old"}}
+			]}}`},
+		})
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		res, err := client.Product.Upsert(ctx, ProductUpsertKey{SKU: "S-1"}, ProductUpsert{
+			Product:  model.ProductInput{Title: &title},
+			Variants: []model.ProductVariantsBulkInput{upsertVariant("S-1", "11.00"), upsertVariant("S-2", "12.00")},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, UpsertActionUpdated, res.Action)
+		assert.Equal(t, `sku:"S-1"`, gql.variables("productVariants")[0]["query"])
+		assert.Equal(t, "c1", gql.variables("productVariants")[1]["cursor"])
+		assert.Equal(t, "gid://shopify/Product/1", gql.variables("product")[0]["id"])
+		input := gql.variables("productUpdate")[0]["input"].(model.ProductInput)
+		assert.Equal(t, "gid://shopify/Product/1", *input.ID)
+		assert.Nil(t, input.Variants)
+		assert.Equal(t, []string{"S-1"}, res.VariantsUpdated)
+		assert.Equal(t, []string{"S-2"}, res.VariantsCreated)
+		assert.Equal(t, []string{"gid://shopify/ProductVariant/2"}, res.VariantsDeleted)
+		assert.Equal(t, []string{"productUpdate", "productVariantsBulkUpdate", "productVariantsBulkDelete", "productVariantsBulkCreate"}, gql.mutations())
+		updated := gql.variables("productVariantsBulkUpdate")[0]["variants"].([]model.ProductVariantsBulkInput)
+		assert.Equal(t, "gid://shopify/ProductVariant/1", *updated[0].ID)
+		created := gql.variables("productVariantsBulkCreate")[0]["variants"].([]model.ProductVariantsBulkInput)
+		assert.Nil(t, created[0].ID)
+		assert.Equal(t, []graphql.ID{"gid://shopify/ProductVariant/2"}, gql.variables("productVariantsBulkDelete")[0]["variantsIds"])
+	})
+
+	t.Run("replace every variant", func(t *testing.T) {
+		gql := upsertGraphQL(map[string][]string{"productByHandle": {`{"id":"gid://shopify/Product/1","variants":{"edges":[
+			{"node":{"id":"gid://shopify/ProductVariant/1","sku":"S-old"}}
+		]}}`}})
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		res, err := client.Product.Upsert(ctx, ProductUpsertKey{Handle: "shirt"}, ProductUpsert{
+			Variants: []model.ProductVariantsBulkInput{upsertVariant("S-1", "10.00")},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"gid://shopify/ProductVariant/1"}, res.VariantsDeleted)
+		// The product can't be left without variants, so the new ones are created first.
+		assert.Equal(t, []string{"productUpdate", "productVariantsBulkCreate", "productVariantsBulkDelete"}, gql.mutations())
+	})
+
+	t.Run("update without variants", func(t *testing.T) {
+		gql := upsertGraphQL(map[string][]string{"productByHandle": {`{"id":"gid://shopify/Product/1","variants":{"edges":[
+			{"node":{"id":"gid://shopify/ProductVariant/1","sku":"S-1"}}
+		]}}`}})
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		res, err := client.Product.Upsert(ctx, ProductUpsertKey{Handle: "shirt"}, ProductUpsert{
+			Product: model.ProductInput{Title: &title},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, UpsertActionUpdated, res.Action)
+		assert.Empty(t, res.VariantsDeleted)
+		assert.Equal(t, []string{"productUpdate"}, gql.mutations())
+	})
+
+	t.Run("ambiguous SKU", func(t *testing.T) {
+		gql := upsertGraphQL(map[string][]string{"productVariants": {`{"edges":[
+			{"node":{"sku":"S-1","product":{"id":"gid://shopify/Product/1"}}},
+			{"node":{"sku":"S-10","product":{"id":"gid://shopify/Product/3"}}},
+			{"node":{"sku":"S-1","product":{"id":"gid://shopify/Product/2"}}}
+		]}`}})
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		_, err := client.Product.Upsert(ctx, ProductUpsertKey{SKU: "S-1"}, ProductUpsert{})
+		assert.EqualError(t, err, "the SKU `S-1` matches several products")
+		assert.Empty(t, gql.mutations())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		client := NewClient("test", WithGraphQLClient(upsertGraphQL(nil)))
+
+		_, err := client.Product.Upsert(ctx, ProductUpsertKey{}, ProductUpsert{})
+		assert.EqualError(t, err, "either Handle or SKU must be set")
+
+		_, err = client.Product.Upsert(ctx, ProductUpsertKey{Handle: "shirt"}, ProductUpsert{
+			Variants: []model.ProductVariantsBulkInput{upsertVariant("S-1", "10.00"), upsertVariant("S-1", "12.00")},
+		})
+		assert.EqualError(t, err, "duplicate variant SKU `S-1`")
+
+		_, err = client.Product.Upsert(ctx, ProductUpsertKey{Handle: "shirt"}, ProductUpsert{
+			Variants: []model.ProductVariantsBulkInput{{}},
+		})
+		assert.EqualError(t, err, "every variant must have a SKU to be matched")
+
+		_, err = client.Product.Upsert(ctx, ProductUpsertKey{Handle: "shirt"}, ProductUpsert{
+			Product: model.ProductInput{Variants: []model.ProductVariantInput{{}}},
+		})
+		assert.EqualError(t, err, "the variants must be set in ProductUpsert.Variants, not in Product.Variants")
+	})
+}
@@ -0,0 +1,235 @@
+package shopify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/vinhluan/go-graphql-client"
+)
+
+//go:generate mockgen -destination=./mock/publication_service.go -package=mock . PublicationService
+type PublicationService interface {
+	ListPublications(ctx context.Context) ([]*model.Publication, error)
+	ListChannels(ctx context.Context) ([]*model.Channel, error)
+
+	// Publish publishes the product or collection to the publications, at their PublishDate when it's set and the
+	// publication supports future publishing.
+	Publish(ctx context.Context, id string, publications ...model.PublicationInput) error
+	Unpublish(ctx context.Context, id string, publicationIDs ...string) error
+
+	// GetProductAvailability returns whether the product is published on each of the publications it can be
+	// published on.
+	GetProductAvailability(ctx context.Context, productID string) ([]*model.ResourcePublicationV2, error)
+}
+
+type PublicationServiceOp struct {
+	client *Client
+}
+
+var _ PublicationService = &PublicationServiceOp{}
+
+type mutationPublishablePublish struct {
+	PublishablePublishResult struct {
+		UserErrors []model.UserError `json:"userErrors,omitempty"`
+	} `graphql:"publishablePublish(id: $id, input: $input)" json:"publishablePublish"`
+}
+
+type mutationPublishableUnpublish struct {
+	PublishableUnpublishResult struct {
+		UserErrors []model.UserError `json:"userErrors,omitempty"`
+	} `graphql:"publishableUnpublish(id: $id, input: $input)" json:"publishableUnpublish"`
+}
+
+func (s *PublicationServiceOp) ListPublications(ctx context.Context) ([]*model.Publication, error) {
+	q := `
+		query publications($cursor: String) {
+			publications(first:250, after: $cursor){
+				nodes{
+					id
+					name
+					autoPublish
+					supportsFuturePublishing
+					app{
+						id
+						title
+						handle
+					}
+				}
+				pageInfo{
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	`
+
+	res := []*model.Publication{}
+	vars := map[string]interface{}{}
+	for {
+		out := struct {
+			Publications *model.PublicationConnection `json:"publications"`
+		}{}
+		err := s.client.QueryString(ctx, q, vars, &out)
+		if err != nil {
+			return nil, fmt.Errorf("query: %w", err)
+		}
+		if out.Publications == nil {
+			return res, nil
+		}
+		for i := range out.Publications.Nodes {
+			res = append(res, &out.Publications.Nodes[i])
+		}
+
+		cursor, ok := nextPageCursor(out.Publications.PageInfo)
+		if !ok {
+			return res, nil
+		}
+		vars["cursor"] = cursor
+	}
+}
+
+func (s *PublicationServiceOp) ListChannels(ctx context.Context) ([]*model.Channel, error) {
+	q := `
+		query channels($cursor: String) {
+			channels(first:250, after: $cursor){
+				nodes{
+					id
+					name
+					handle
+					supportsFuturePublishing
+					app{
+						id
+						title
+						handle
+					}
+				}
+				pageInfo{
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	`
+
+	res := []*model.Channel{}
+	vars := map[string]interface{}{}
+	for {
+		out := struct {
+			Channels *model.ChannelConnection `json:"channels"`
+		}{}
+		err := s.client.QueryString(ctx, q, vars, &out)
+		if err != nil {
+			return nil, fmt.Errorf("query: %w", err)
+		}
+		if out.Channels == nil {
+			return res, nil
+		}
+		for i := range out.Channels.Nodes {
+			res = append(res, &out.Channels.Nodes[i])
+		}
+
+		cursor, ok := nextPageCursor(out.Channels.PageInfo)
+		if !ok {
+			return res, nil
+		}
+		vars["cursor"] = cursor
+	}
+}
+
+func (s *PublicationServiceOp) Publish(ctx context.Context, id string, publications ...model.PublicationInput) error {
+	m := mutationPublishablePublish{}
+
+	vars := map[string]interface{}{
+		"id":    graphql.ID(id),
+		"input": publications,
+	}
+	err := s.client.Mutate(ctx, &m, vars)
+	if err != nil {
+		return fmt.Errorf("mutation: %w", err)
+	}
+
+	if len(m.PublishablePublishResult.UserErrors) > 0 {
+		return fmt.Errorf("%+v", m.PublishablePublishResult.UserErrors)
+	}
+
+	return nil
+}
+
+func (s *PublicationServiceOp) Unpublish(ctx context.Context, id string, publicationIDs ...string) error {
+	m := mutationPublishableUnpublish{}
+
+	input := make([]model.PublicationInput, len(publicationIDs))
+	for i := range publicationIDs {
+		input[i].PublicationID = &publicationIDs[i]
+	}
+	vars := map[string]interface{}{
+		"id":    graphql.ID(id),
+		"input": input,
+	}
+	err := s.client.Mutate(ctx, &m, vars)
+	if err != nil {
+		return fmt.Errorf("mutation: %w", err)
+	}
+
+	if len(m.PublishableUnpublishResult.UserErrors) > 0 {
+		return fmt.Errorf("%+v", m.PublishableUnpublishResult.UserErrors)
+	}
+
+	return nil
+}
+
+func (s *PublicationServiceOp) GetProductAvailability(ctx context.Context, productID string) ([]*model.ResourcePublicationV2, error) {
+	q := `
+		query productAvailability($id: ID!, $cursor: String) {
+			product(id: $id){
+				resourcePublicationsV2(first:250, after: $cursor, onlyPublished: false){
+					nodes{
+						isPublished
+						publishDate
+						publication{
+							id
+							name
+						}
+					}
+					pageInfo{
+						hasNextPage
+						endCursor
+					}
+				}
+			}
+		}
+	`
+
+	res := []*model.ResourcePublicationV2{}
+	vars := map[string]interface{}{
+		"id": productID,
+	}
+	for {
+		out := struct {
+			Product *struct {
+				ResourcePublicationsV2 *model.ResourcePublicationV2Connection `json:"resourcePublicationsV2"`
+			} `json:"product"`
+		}{}
+		err := s.client.QueryString(ctx, q, vars, &out)
+		if err != nil {
+			return nil, fmt.Errorf("query: %w", err)
+		}
+		if out.Product == nil {
+			return nil, fmt.Errorf("product `%s` not found", productID)
+		}
+		conn := out.Product.ResourcePublicationsV2
+		if conn == nil {
+			return res, nil
+		}
+		for i := range conn.Nodes {
+			res = append(res, &conn.Nodes[i])
+		}
+
+		cursor, ok := nextPageCursor(conn.PageInfo)
+		if !ok {
+			return res, nil
+		}
+		vars["cursor"] = cursor
+	}
+}
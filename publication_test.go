@@ -0,0 +1,66 @@
+package shopify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vinhluan/go-graphql-client"
+)
+
+func TestListPublications(t *testing.T) {
+	gql := &queuedGraphQL{responses: map[string][]string{"publications": {
+		`{"nodes":[{"id":"gid://shopify/Publication/1","name":"Online Store"}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}`,
+		`{"nodes":[{"id":"gid://shopify/Publication/2","name":"Point of Sale","app":{"title":"Point of Sale"}}],"pageInfo":{"hasNextPage":false}}`,
+	}}}
+	client := NewClient("test", WithGraphQLClient(gql))
+
+	publications, err := client.Publication.ListPublications(context.Background())
+	require.NoError(t, err)
+	require.Len(t, publications, 2)
+	assert.Equal(t, "Online Store", publications[0].Name)
+	assert.Equal(t, "Point of Sale", publications[1].App.Title)
+	assert.Empty(t, gql.variables("publications")[0])
+	assert.Equal(t, "c1", gql.variables("publications")[1]["cursor"])
+}
+
+func TestUnpublish(t *testing.T) {
+	gql := &queuedGraphQL{}
+	client := NewClient("test", WithGraphQLClient(gql))
+
+	err := client.Publication.Unpublish(context.Background(), "gid://shopify/Product/1", "gid://shopify/Publication/1", "gid://shopify/Publication/2")
+	require.NoError(t, err)
+	input := gql.variables("publishableUnpublish")[0]["input"].([]model.PublicationInput)
+	require.Len(t, input, 2)
+	assert.Equal(t, "gid://shopify/Publication/1", *input[0].PublicationID)
+	assert.Equal(t, "gid://shopify/Publication/2", *input[1].PublicationID)
+	assert.Equal(t, graphql.ID("gid://shopify/Product/1"), gql.variables("publishableUnpublish")[0]["id"])
+}
+
+func TestGetProductAvailability(t *testing.T) {
+	t.Run("availability", func(t *testing.T) {
+		gql := &queuedGraphQL{responses: map[string][]string{"product": {
+			`{"resourcePublicationsV2":{"nodes":[
+				{"isPublished":true,"publication":{"id":"gid://shopify/Publication/1","name":"Online Store"}},
+				{"isPublished":false,"publication":{"id":"gid://shopify/Publication/2","name":"Point of Sale"}}
+			],"pageInfo":{"hasNextPage":false}}}`,
+		}}}
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		availability, err := client.Publication.GetProductAvailability(context.Background(), "gid://shopify/Product/1")
+		require.NoError(t, err)
+		require.Len(t, availability, 2)
+		assert.True(t, availability[0].IsPublished)
+		assert.Equal(t, "Point of Sale", availability[1].Publication.Name)
+		assert.False(t, availability[1].IsPublished)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client := NewClient("test", WithGraphQLClient(&queuedGraphQL{responses: map[string][]string{"product": {`null`}}}))
+
+		_, err := client.Publication.GetProductAvailability(context.Background(), "gid://shopify/Product/1")
+		assert.EqualError(t, err, "product `gid://shopify/Product/1` not found")
+	})
+}
@@ -0,0 +1,206 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position is a 1-based line and column in a GraphQL source.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+type OperationType string
+
+const (
+	OperationQuery        OperationType = "query"
+	OperationMutation     OperationType = "mutation"
+	OperationSubscription OperationType = "subscription"
+)
+
+// QueryDocument is a parsed executable GraphQL document.
+type QueryDocument struct {
+	Operations []*Operation
+	Fragments  []*Fragment
+}
+
+// Fragment returns the fragment definition with the given name or nil.
+func (d *QueryDocument) Fragment(name string) *Fragment {
+	for _, f := range d.Fragments {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+type Operation struct {
+	Type                OperationType
+	Name                string
+	VariableDefinitions []*VariableDefinition
+	SelectionSet        SelectionSet
+	Position            Position
+}
+
+type VariableDefinition struct {
+	Name         string
+	Type         *TypeRef
+	DefaultValue *Value
+	Position     Position
+}
+
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	SelectionSet  SelectionSet
+	Position      Position
+}
+
+type SelectionSet []Selection
+
+// Selection is one of *Field, *InlineFragment or *FragmentSpread.
+type Selection interface {
+	Pos() Position
+}
+
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    []*Argument
+	SelectionSet SelectionSet
+	Position     Position
+}
+
+func (f *Field) Pos() Position { return f.Position }
+
+// Argument returns the argument with the given name or nil.
+func (f *Field) Argument(name string) *Argument {
+	for _, a := range f.Arguments {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// ResponseKey is the key the field is returned under, the alias if present or otherwise the field name.
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+type InlineFragment struct {
+	TypeCondition string
+	SelectionSet  SelectionSet
+	Position      Position
+}
+
+func (f *InlineFragment) Pos() Position { return f.Position }
+
+type FragmentSpread struct {
+	Name     string
+	Position Position
+}
+
+func (f *FragmentSpread) Pos() Position { return f.Position }
+
+type Argument struct {
+	Name     string
+	Value    *Value
+	Position Position
+}
+
+type ValueKind int
+
+const (
+	VariableValue ValueKind = iota
+	IntValue
+	FloatValue
+	StringValue
+	BooleanValue
+	NullValue
+	EnumValue
+	ListValue
+	ObjectValue
+)
+
+// Value is an argument or default value. Raw holds the variable name without `$`, the unquoted string or the literal
+// as written for scalars; List and Fields hold the children of list and object values.
+type Value struct {
+	Kind     ValueKind
+	Raw      string
+	List     []*Value
+	Fields   []*ObjectField
+	Position Position
+}
+
+type ObjectField struct {
+	Name  string
+	Value *Value
+}
+
+// Variables returns the names of all variables referenced by the value.
+func (v *Value) Variables() []string {
+	if v == nil {
+		return nil
+	}
+	switch v.Kind {
+	case VariableValue:
+		return []string{v.Raw}
+	case ListValue:
+		var names []string
+		for _, item := range v.List {
+			names = append(names, item.Variables()...)
+		}
+		return names
+	case ObjectValue:
+		var names []string
+		for _, f := range v.Fields {
+			names = append(names, f.Value.Variables()...)
+		}
+		return names
+	}
+	return nil
+}
+
+// TypeRef is a reference to a named type, a list (Elem is set) or their non-null variants.
+type TypeRef struct {
+	Name    string
+	Elem    *TypeRef
+	NonNull bool
+}
+
+// NamedType returns the innermost named type, unwrapping lists and non-null.
+func (t *TypeRef) NamedType() string {
+	for t.Elem != nil {
+		t = t.Elem
+	}
+	return t.Name
+}
+
+// IsList reports whether the reference is a list type.
+func (t *TypeRef) IsList() bool {
+	return t.Elem != nil
+}
+
+func (t *TypeRef) String() string {
+	var sb strings.Builder
+	if t.Elem != nil {
+		sb.WriteString("[")
+		sb.WriteString(t.Elem.String())
+		sb.WriteString("]")
+	} else {
+		sb.WriteString(t.Name)
+	}
+	if t.NonNull {
+		sb.WriteString("!")
+	}
+	return sb.String()
+}
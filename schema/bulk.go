@@ -0,0 +1,136 @@
+package schema
+
+const (
+	// MaxBulkConnections is the count of connections a bulk query can hold.
+	MaxBulkConnections = 5
+	// MaxBulkNestedConnections is the depth of connections a bulk query can nest under its top level connection.
+	MaxBulkNestedConnections = 2
+)
+
+type bulkValidator struct {
+	validator
+	connections int
+}
+
+// ValidateBulkQuery parses the document of a bulk query and checks the constraints of bulk operations, which don't
+// depend on the schema: a single query operation with no top level `node` or `nodes` field, at most
+// MaxBulkConnections connections nested at most MaxBulkNestedConnections levels deep, and the `id` field selected on
+// the nodes of nested connections and of the connections they're nested in, as the results link them by ID. The
+// returned error is of type Errors.
+func ValidateBulkQuery(src string) error {
+	doc, err := ParseQuery(src)
+	if err != nil {
+		return err
+	}
+	return ValidateBulkDocument(doc)
+}
+
+// ValidateBulkDocument checks the constraints of bulk operations on a parsed document, see ValidateBulkQuery.
+func ValidateBulkDocument(doc *QueryDocument) error {
+	v := &bulkValidator{validator: validator{doc: doc}}
+
+	if len(doc.Operations) != 1 {
+		v.errorf(Position{}, nil, "a bulk query must hold a single operation, got %d", len(doc.Operations))
+		return v.err()
+	}
+	op := doc.Operations[0]
+	if op.Type != OperationQuery {
+		v.errorf(op.Position, nil, "a bulk query must be a query, got a %s", op.Type)
+		return v.err()
+	}
+
+	for _, f := range v.fields(op.SelectionSet, map[string]bool{}) {
+		path := []string{f.ResponseKey()}
+		if f.Name == "node" || f.Name == "nodes" {
+			v.errorf(f.Position, path, "bulk queries don't support the top level `%s` field, query a connection instead", f.Name)
+			continue
+		}
+		v.validateField(f, path, -1)
+	}
+	if v.connections == 0 && len(v.errs) == 0 {
+		v.errorf(op.Position, nil, "a bulk query must query a connection")
+	}
+
+	return v.err()
+}
+
+// validateField checks the field and its selection. depth is the count of connections the field is nested in
+// under the top level one, or -1 above it.
+func (v *bulkValidator) validateField(f *Field, path []string, depth int) {
+	node := v.connectionNode(f)
+	if node == nil {
+		for _, child := range v.fields(f.SelectionSet, map[string]bool{}) {
+			v.validateField(child, append(path, child.ResponseKey()), depth)
+		}
+		return
+	}
+
+	v.connections++
+	depth++
+	nodePath := append(append([]string(nil), path...), "edges", "node")
+	if v.connections == MaxBulkConnections+1 {
+		v.errorf(f.Position, path, "bulk queries can hold at most %d connections", MaxBulkConnections)
+	}
+	if depth > MaxBulkNestedConnections {
+		v.errorf(f.Position, path, "bulk queries can nest connections at most %d levels deep", MaxBulkNestedConnections)
+		return
+	}
+
+	fields := v.fields(node.SelectionSet, map[string]bool{})
+	hasID := false
+	hasNested := false
+	for _, child := range fields {
+		if child.Name == "id" && child.Alias == "" {
+			hasID = true
+		}
+		if v.connectionNode(child) != nil {
+			hasNested = true
+		}
+	}
+	if !hasID && depth > 0 {
+		v.errorf(node.Position, nodePath, "the nodes of nested connections must select the `id` field to be linked to their parent")
+	} else if !hasID && hasNested {
+		v.errorf(node.Position, nodePath, "the nodes of connections with nested connections must select the `id` field to be linked to their children")
+	}
+
+	for _, child := range fields {
+		v.validateField(child, append(nodePath, child.ResponseKey()), depth)
+	}
+}
+
+// connectionNode returns the `node` field selected on the `edges` of the field, when it's a connection.
+func (v *bulkValidator) connectionNode(f *Field) *Field {
+	for _, edges := range v.fields(f.SelectionSet, map[string]bool{}) {
+		if edges.Name != "edges" {
+			continue
+		}
+		for _, node := range v.fields(edges.SelectionSet, map[string]bool{}) {
+			if node.Name == "node" {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+// fields returns the fields of the selection set, including the ones of its inline fragments and fragment spreads.
+func (v *bulkValidator) fields(set SelectionSet, visited map[string]bool) []*Field {
+	var fields []*Field
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *Field:
+			fields = append(fields, sel)
+		case *InlineFragment:
+			fields = append(fields, v.fields(sel.SelectionSet, visited)...)
+		case *FragmentSpread:
+			if visited[sel.Name] {
+				continue
+			}
+			visited[sel.Name] = true
+			if frag := v.doc.Fragment(sel.Name); frag != nil {
+				fields = append(fields, v.fields(frag.SelectionSet, visited)...)
+			}
+		}
+	}
+	return fields
+}
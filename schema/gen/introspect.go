@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const introspectionQuery = `
+	query IntrospectionQuery {
+		__schema {
+			queryType { name }
+			mutationType { name }
+			types { ...FullType }
+		}
+	}
+
+	fragment FullType on __Type {
+		kind
+		name
+		fields(includeDeprecated: true) {
+			name
+			args { ...InputValue }
+			type { ...TypeRef }
+		}
+		inputFields { ...InputValue }
+		interfaces { ...TypeRef }
+		enumValues(includeDeprecated: true) { name }
+		possibleTypes { ...TypeRef }
+	}
+
+	fragment InputValue on __InputValue {
+		name
+		type { ...TypeRef }
+		defaultValue
+	}
+
+	fragment TypeRef on __Type {
+		kind
+		name
+		ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } }
+	}
+`
+
+type introspectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	OfType *introspectionTypeRef `json:"ofType"`
+}
+
+func (t *introspectionTypeRef) String() string {
+	switch t.Kind {
+	case "NON_NULL":
+		return t.OfType.String() + "!"
+	case "LIST":
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+type introspectionInputValue struct {
+	Name         string                `json:"name"`
+	Type         *introspectionTypeRef `json:"type"`
+	DefaultValue *string               `json:"defaultValue"`
+}
+
+type introspectionType struct {
+	Kind          string                    `json:"kind"`
+	Name          string                    `json:"name"`
+	Fields        []introspectionField      `json:"fields"`
+	InputFields   []introspectionInputValue `json:"inputFields"`
+	Interfaces    []introspectionTypeRef    `json:"interfaces"`
+	EnumValues    []struct{ Name string }   `json:"enumValues"`
+	PossibleTypes []introspectionTypeRef    `json:"possibleTypes"`
+}
+
+type introspectionField struct {
+	Name string                    `json:"name"`
+	Args []introspectionInputValue `json:"args"`
+	Type *introspectionTypeRef     `json:"type"`
+}
+
+type introspectionSchema struct {
+	QueryType    *struct{ Name string } `json:"queryType"`
+	MutationType *struct{ Name string } `json:"mutationType"`
+	Types        []introspectionType    `json:"types"`
+}
+
+// introspect returns the introspection of the schema served at source, an Admin API GraphQL endpoint queried with
+// the SHOPIFY_ACCESS_TOKEN, or the path of an introspection result saved as JSON.
+func introspect(source string) (*introspectionSchema, error) {
+	var r io.Reader
+	if strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") {
+		body, err := json.Marshal(map[string]string{"query": introspectionQuery})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequest(http.MethodPost, source, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Shopify-Access-Token", os.Getenv("SHOPIFY_ACCESS_TOKEN"))
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("introspect %s: %s", source, res.Status)
+		}
+		r = res.Body
+	} else {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	// Accept both the full response and its data.
+	out := struct {
+		Data *struct {
+			Schema *introspectionSchema `json:"__schema"`
+		} `json:"data"`
+		Schema *introspectionSchema `json:"__schema"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}{}
+	err := json.NewDecoder(r).Decode(&out)
+	if err != nil {
+		return nil, fmt.Errorf("decode introspection: %w", err)
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("introspect %s: %s", source, out.Errors[0].Message)
+	}
+	if out.Data != nil && out.Data.Schema != nil {
+		return out.Data.Schema, nil
+	}
+	if out.Schema == nil {
+		return nil, fmt.Errorf("no __schema in the introspection of %s", source)
+	}
+	return out.Schema, nil
+}
+
+func (s *introspectionSchema) sdl(source string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Code generated by schema/gen from the introspection of %s, DO NOT EDIT.\n\n", redactSource(source))
+	buf.WriteString("schema {\n")
+	if s.QueryType != nil {
+		fmt.Fprintf(&buf, "\tquery: %s\n", s.QueryType.Name)
+	}
+	if s.MutationType != nil {
+		fmt.Fprintf(&buf, "\tmutation: %s\n", s.MutationType.Name)
+	}
+	buf.WriteString("}\n")
+
+	types := append([]introspectionType(nil), s.Types...)
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	for _, t := range types {
+		if strings.HasPrefix(t.Name, "__") {
+			continue
+		}
+		switch t.Kind {
+		case "SCALAR":
+			switch t.Name {
+			case "String", "Int", "Float", "Boolean", "ID":
+			default:
+				fmt.Fprintf(&buf, "\nscalar %s\n", t.Name)
+			}
+		case "OBJECT", "INTERFACE":
+			keyword := "type"
+			if t.Kind == "INTERFACE" {
+				keyword = "interface"
+			}
+			fmt.Fprintf(&buf, "\n%s %s", keyword, t.Name)
+			if len(t.Interfaces) > 0 {
+				names := make([]string, len(t.Interfaces))
+				for i, iface := range t.Interfaces {
+					names[i] = iface.Name
+				}
+				buf.WriteString(" implements " + strings.Join(names, " & "))
+			}
+			buf.WriteString(" {\n")
+			for _, f := range t.Fields {
+				buf.WriteString("\t" + f.Name)
+				if len(f.Args) > 0 {
+					args := make([]string, len(f.Args))
+					for i, a := range f.Args {
+						args[i] = inputValueSDL(a)
+					}
+					buf.WriteString("(" + strings.Join(args, ", ") + ")")
+				}
+				buf.WriteString(": " + f.Type.String() + "\n")
+			}
+			buf.WriteString("}\n")
+		case "INPUT_OBJECT":
+			fmt.Fprintf(&buf, "\ninput %s {\n", t.Name)
+			for _, f := range t.InputFields {
+				buf.WriteString("\t" + inputValueSDL(f) + "\n")
+			}
+			buf.WriteString("}\n")
+		case "UNION":
+			names := make([]string, len(t.PossibleTypes))
+			for i, pt := range t.PossibleTypes {
+				names[i] = pt.Name
+			}
+			fmt.Fprintf(&buf, "\nunion %s = %s\n", t.Name, strings.Join(names, " | "))
+		case "ENUM":
+			fmt.Fprintf(&buf, "\nenum %s {\n", t.Name)
+			for _, v := range t.EnumValues {
+				buf.WriteString("\t" + v.Name + "\n")
+			}
+			buf.WriteString("}\n")
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func inputValueSDL(v introspectionInputValue) string {
+	s := v.Name + ": " + v.Type.String()
+	if v.DefaultValue != nil {
+		s += " = " + *v.DefaultValue
+	}
+	return s
+}
+
+// redactSource keeps the store and local paths out of the generated header.
+func redactSource(source string) string {
+	if i := strings.Index(source, "/admin/api/"); i >= 0 {
+		return "the Admin API " + strings.TrimSuffix(strings.TrimPrefix(source[i:], "/admin/api/"), "/graphql.json")
+	}
+	return filepath.Base(source)
+}
@@ -0,0 +1,350 @@
+// Command gen writes a schema snapshot in SDL, from the introspection of the Admin API or, as a fallback without
+// field arguments, from the gqlgen models of the model package.
+//
+//	SHOPIFY_ACCESS_TOKEN=... go run ./gen -introspect https://<store>.myshopify.com/admin/api/2023-04/graphql.json -out 2023-04.graphql
+//	go run ./gen -introspect introspection.json -out 2023-04.graphql
+//	go run ./gen -models ../model/models.go -out 2023-04.models.graphql
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var mutationComment = regexp.MustCompile("^Return type for `(\\w+)` mutation\\.")
+
+// gqlgen upper-cases these initialisms in Go type names, while the Admin API spells them e.g. `WebhookHttpEndpoint`.
+var initialisms = strings.NewReplacer("API", "Api", "HTTP", "Http", "URL", "Url")
+
+// graphqlName returns the schema name of a Go model type.
+func graphqlName(goName string) string {
+	return initialisms.Replace(goName)
+}
+
+type goField struct {
+	goName   string
+	jsonName string
+	typ      ast.Expr
+	nonNull  bool
+}
+
+type models struct {
+	structs    map[string][]goField
+	enums      map[string][]string
+	interfaces map[string][]string // interface name -> getter names without the Get prefix
+	implements map[string][]string // type name -> marker interfaces
+	mutations  map[string]string   // mutation name -> payload type
+}
+
+func main() {
+	modelsPath := flag.String("models", "../model/models.go", "path of the gqlgen models file")
+	source := flag.String("introspect", "", "Admin API GraphQL endpoint or introspection JSON file to generate from instead of the models")
+	out := flag.String("out", "", "output SDL file")
+	flag.Parse()
+
+	if *out == "" {
+		log.Fatalln("-out is required")
+	}
+
+	var sdl []byte
+	if *source != "" {
+		s, err := introspect(*source)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		sdl = s.sdl(*source)
+	} else {
+		m, err := load(*modelsPath)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		sdl = m.sdl()
+	}
+
+	err := os.WriteFile(*out, sdl, 0o644)
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func load(path string) (*models, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	m := &models{
+		structs:    make(map[string][]goField),
+		enums:      make(map[string][]string),
+		interfaces: make(map[string][]string),
+		implements: make(map[string][]string),
+		mutations:  make(map[string]string),
+	}
+
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					m.addType(spec, decl.Doc)
+				case *ast.ValueSpec:
+					m.addEnumValue(spec)
+				}
+			}
+		case *ast.FuncDecl:
+			m.addMarker(decl)
+		}
+	}
+
+	return m, nil
+}
+
+func (m *models) addType(spec *ast.TypeSpec, doc *ast.CommentGroup) {
+	name := spec.Name.Name
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		var fields []goField
+		for _, field := range t.Fields.List {
+			if field.Tag == nil || len(field.Names) == 0 {
+				continue
+			}
+			tag, _ := strconv.Unquote(field.Tag.Value)
+			jsonTag := reflect.StructTag(tag).Get("json")
+			parts := strings.Split(jsonTag, ",")
+			if parts[0] == "" || parts[0] == "-" {
+				continue
+			}
+			omitempty := false
+			for _, p := range parts[1:] {
+				omitempty = omitempty || p == "omitempty"
+			}
+			_, isPtr := field.Type.(*ast.StarExpr)
+			fields = append(fields, goField{
+				goName:   field.Names[0].Name,
+				jsonName: parts[0],
+				typ:      field.Type,
+				nonNull:  !isPtr && !omitempty,
+			})
+		}
+		m.structs[name] = fields
+
+		if doc != nil {
+			if sm := mutationComment.FindStringSubmatch(strings.TrimSpace(doc.Text())); sm != nil {
+				m.mutations[sm[1]] = name
+			}
+		}
+	case *ast.InterfaceType:
+		getters := []string{}
+		for _, method := range t.Methods.List {
+			for _, n := range method.Names {
+				if strings.HasPrefix(n.Name, "Get") {
+					getters = append(getters, strings.TrimPrefix(n.Name, "Get"))
+				}
+			}
+		}
+		m.interfaces[name] = getters
+	case *ast.Ident:
+		if t.Name == "string" {
+			if _, ok := m.enums[name]; !ok {
+				m.enums[name] = nil
+			}
+		}
+	}
+}
+
+func (m *models) addEnumValue(spec *ast.ValueSpec) {
+	ident, ok := spec.Type.(*ast.Ident)
+	if !ok || len(spec.Values) != 1 {
+		return
+	}
+	lit, ok := spec.Values[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return
+	}
+	m.enums[ident.Name] = append(m.enums[ident.Name], value)
+}
+
+// addMarker records gqlgen marker methods such as `func (Product) IsNode() {}`.
+func (m *models) addMarker(decl *ast.FuncDecl) {
+	if decl.Recv == nil || len(decl.Recv.List) != 1 || !strings.HasPrefix(decl.Name.Name, "Is") {
+		return
+	}
+	recv, ok := decl.Recv.List[0].Type.(*ast.Ident)
+	if !ok {
+		return
+	}
+	m.implements[recv.Name] = append(m.implements[recv.Name], strings.TrimPrefix(decl.Name.Name, "Is"))
+}
+
+func (m *models) isUnion(name string) bool {
+	getters, ok := m.interfaces[name]
+	return ok && len(getters) == 0
+}
+
+func (m *models) typeRef(expr ast.Expr, field string, nonNull bool) string {
+	var ref string
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return m.typeRef(t.X, field, false)
+	case *ast.ArrayType:
+		_, elemPtr := t.Elt.(*ast.StarExpr)
+		ref = "[" + m.typeRef(t.Elt, field, !elemPtr) + "]"
+	case *ast.SelectorExpr:
+		// null.String and friends
+		ref = t.Sel.Name
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			ref = "String"
+			if field == "ID" || strings.HasSuffix(field, "ID") && !strings.HasSuffix(field, "IDs") {
+				ref = "ID"
+			}
+		case "int", "int64":
+			ref = "Int"
+		case "float64":
+			ref = "Float"
+		case "bool":
+			ref = "Boolean"
+		default:
+			ref = graphqlName(t.Name)
+		}
+	default:
+		ref = "String"
+	}
+	if nonNull {
+		ref += "!"
+	}
+	return ref
+}
+
+func (m *models) sdl() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# Code generated by schema/gen from model/models.go, DO NOT EDIT.\n\n")
+	buf.WriteString("schema {\n\tquery: QueryRoot\n\tmutation: Mutation\n}\n")
+
+	names := make([]string, 0, len(m.structs))
+	for name := range m.structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var ifaces []string
+		for _, i := range m.implements[name] {
+			if _, ok := m.interfaces[i]; ok && !m.isUnion(i) {
+				ifaces = append(ifaces, graphqlName(i))
+			}
+		}
+		sort.Strings(ifaces)
+
+		buf.WriteString("\ntype " + graphqlName(name))
+		if len(ifaces) > 0 {
+			buf.WriteString(" implements " + strings.Join(ifaces, " & "))
+		}
+		buf.WriteString(" {\n")
+		for _, f := range m.structs[name] {
+			fmt.Fprintf(&buf, "\t%s: %s\n", f.jsonName, m.typeRef(f.typ, f.goName, f.nonNull))
+		}
+		buf.WriteString("}\n")
+	}
+
+	mutations := make([]string, 0, len(m.mutations))
+	for name := range m.mutations {
+		mutations = append(mutations, name)
+	}
+	sort.Strings(mutations)
+	buf.WriteString("\ntype Mutation {\n")
+	for _, name := range mutations {
+		fmt.Fprintf(&buf, "\t%s: %s\n", name, graphqlName(m.mutations[name]))
+	}
+	buf.WriteString("}\n")
+
+	names = names[:0]
+	for name := range m.interfaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var members []string
+		for _, s := range sortedKeys(m.implements) {
+			for _, i := range m.implements[s] {
+				if i == name {
+					members = append(members, s)
+				}
+			}
+		}
+
+		if m.isUnion(name) {
+			names := make([]string, len(members))
+			for i, member := range members {
+				names[i] = graphqlName(member)
+			}
+			fmt.Fprintf(&buf, "\nunion %s = %s\n", graphqlName(name), strings.Join(names, " | "))
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\ninterface %s {\n", graphqlName(name))
+		for _, getter := range m.interfaces[name] {
+			if f, ok := m.memberField(members, getter); ok {
+				fmt.Fprintf(&buf, "\t%s: %s\n", f.jsonName, m.typeRef(f.typ, f.goName, f.nonNull))
+			}
+		}
+		buf.WriteString("}\n")
+	}
+
+	names = names[:0]
+	for name := range m.enums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if len(m.enums[name]) == 0 {
+			fmt.Fprintf(&buf, "\nscalar %s\n", graphqlName(name))
+			continue
+		}
+		fmt.Fprintf(&buf, "\nenum %s {\n", graphqlName(name))
+		for _, v := range m.enums[name] {
+			fmt.Fprintf(&buf, "\t%s\n", v)
+		}
+		buf.WriteString("}\n")
+	}
+
+	return buf.Bytes()
+}
+
+// memberField finds the field behind an interface getter on one of the implementing types.
+func (m *models) memberField(members []string, getter string) (goField, bool) {
+	for _, member := range members {
+		for _, f := range m.structs[member] {
+			if f.goName == getter {
+				return f, true
+			}
+		}
+	}
+	return goField{}, false
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
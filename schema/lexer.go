@@ -0,0 +1,217 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   Position
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "<EOF>"
+	case tokenString:
+		return fmt.Sprintf("%q", t.value)
+	default:
+		return t.value
+	}
+}
+
+// lexer splits a GraphQL source into tokens. Commas, whitespace and comments are insignificant and skipped.
+type lexer struct {
+	src  string
+	off  int
+	line int
+	col  int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src, line: 1, col: 1}
+}
+
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.off < len(l.src); i++ {
+		if l.src[l.off] == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+		l.off++
+	}
+}
+
+func (l *lexer) skipIgnored() {
+	for l.off < len(l.src) {
+		switch c := l.src[l.off]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.advance(1)
+		case c == '#':
+			for l.off < len(l.src) && l.src[l.off] != '\n' {
+				l.advance(1)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+
+	pos := Position{Line: l.line, Column: l.col}
+	if l.off >= len(l.src) {
+		return token{kind: tokenEOF, pos: pos}, nil
+	}
+
+	c := l.src[l.off]
+	switch {
+	case strings.IndexByte("!$&():=@[]{}|", c) >= 0:
+		l.advance(1)
+		return token{kind: tokenPunct, value: string(c), pos: pos}, nil
+	case c == '.':
+		if strings.HasPrefix(l.src[l.off:], "...") {
+			l.advance(3)
+			return token{kind: tokenPunct, value: "...", pos: pos}, nil
+		}
+		return token{}, fmt.Errorf("%s: unexpected character %q", pos, c)
+	case c == '_' || isLetter(c):
+		start := l.off
+		for l.off < len(l.src) && (l.src[l.off] == '_' || isLetter(l.src[l.off]) || isDigit(l.src[l.off])) {
+			l.advance(1)
+		}
+		return token{kind: tokenName, value: l.src[start:l.off], pos: pos}, nil
+	case c == '-' || isDigit(c):
+		return l.number(pos)
+	case c == '"':
+		if strings.HasPrefix(l.src[l.off:], `"""`) {
+			return l.blockString(pos)
+		}
+		return l.string(pos)
+	default:
+		return token{}, fmt.Errorf("%s: unexpected character %q", pos, c)
+	}
+}
+
+func (l *lexer) number(pos Position) (token, error) {
+	start := l.off
+	kind := tokenInt
+	if l.src[l.off] == '-' {
+		l.advance(1)
+	}
+	digits := func() int {
+		n := 0
+		for l.off < len(l.src) && isDigit(l.src[l.off]) {
+			l.advance(1)
+			n++
+		}
+		return n
+	}
+	if digits() == 0 {
+		return token{}, fmt.Errorf("%s: invalid number", pos)
+	}
+	if l.off < len(l.src) && l.src[l.off] == '.' {
+		kind = tokenFloat
+		l.advance(1)
+		if digits() == 0 {
+			return token{}, fmt.Errorf("%s: invalid number", pos)
+		}
+	}
+	if l.off < len(l.src) && (l.src[l.off] == 'e' || l.src[l.off] == 'E') {
+		kind = tokenFloat
+		l.advance(1)
+		if l.off < len(l.src) && (l.src[l.off] == '+' || l.src[l.off] == '-') {
+			l.advance(1)
+		}
+		if digits() == 0 {
+			return token{}, fmt.Errorf("%s: invalid number", pos)
+		}
+	}
+	return token{kind: kind, value: l.src[start:l.off], pos: pos}, nil
+}
+
+func (l *lexer) string(pos Position) (token, error) {
+	l.advance(1)
+	var sb strings.Builder
+	for l.off < len(l.src) {
+		c := l.src[l.off]
+		switch c {
+		case '"':
+			l.advance(1)
+			return token{kind: tokenString, value: sb.String(), pos: pos}, nil
+		case '\n':
+			return token{}, fmt.Errorf("%s: unterminated string", pos)
+		case '\\':
+			if l.off+1 >= len(l.src) {
+				return token{}, fmt.Errorf("%s: unterminated string", pos)
+			}
+			esc := l.src[l.off+1]
+			switch esc {
+			case '"', '\\', '/':
+				sb.WriteByte(esc)
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if l.off+6 > len(l.src) {
+					return token{}, fmt.Errorf("%s: invalid unicode escape", pos)
+				}
+				var r rune
+				if _, err := fmt.Sscanf(l.src[l.off+2:l.off+6], "%04x", &r); err != nil {
+					return token{}, fmt.Errorf("%s: invalid unicode escape", pos)
+				}
+				sb.WriteRune(r)
+				l.advance(4)
+			default:
+				return token{}, fmt.Errorf("%s: invalid escape sequence \\%c", pos, esc)
+			}
+			l.advance(2)
+		default:
+			sb.WriteByte(c)
+			l.advance(1)
+		}
+	}
+	return token{}, fmt.Errorf("%s: unterminated string", pos)
+}
+
+func (l *lexer) blockString(pos Position) (token, error) {
+	l.advance(3)
+	end := strings.Index(l.src[l.off:], `"""`)
+	if end < 0 {
+		return token{}, fmt.Errorf("%s: unterminated block string", pos)
+	}
+	value := l.src[l.off : l.off+end]
+	l.advance(end + 3)
+	return token{kind: tokenString, value: strings.TrimSpace(value), pos: pos}, nil
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
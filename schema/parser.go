@@ -0,0 +1,470 @@
+package schema
+
+import (
+	"fmt"
+)
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+// ParseQuery parses an executable GraphQL document: operations (including the `{ ... }` shorthand) and fragments.
+func ParseQuery(src string) (*QueryDocument, error) {
+	p, err := newParser(src)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &QueryDocument{}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peekPunct("{"):
+			set, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &Operation{Type: OperationQuery, SelectionSet: set, Position: set[0].Pos()})
+		case p.peekName("query"), p.peekName("mutation"), p.peekName("subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.peekName("fragment"):
+			f, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			doc.Fragments = append(doc.Fragments, f)
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	return doc, nil
+}
+
+// ParseSelectionSet parses a bare selection set such as `id title variants{ edges{ node{ id } } }`, with or without
+// the surrounding braces.
+func ParseSelectionSet(src string) (SelectionSet, error) {
+	p, err := newParser(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peekPunct("{") {
+		set, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenEOF {
+			return nil, p.unexpected()
+		}
+		return set, nil
+	}
+
+	var set SelectionSet
+	for p.tok.kind != tokenEOF {
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, sel)
+	}
+	return set, nil
+}
+
+func newParser(src string) (*parser, error) {
+	p := &parser{lex: newLexer(src)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) peekPunct(value string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == value
+}
+
+func (p *parser) peekName(value string) bool {
+	return p.tok.kind == tokenName && p.tok.value == value
+}
+
+func (p *parser) unexpected() error {
+	return fmt.Errorf("%s: unexpected %s", p.tok.pos, p.tok)
+}
+
+func (p *parser) expectPunct(value string) error {
+	if !p.peekPunct(value) {
+		return fmt.Errorf("%s: expected %q, got %s", p.tok.pos, value, p.tok)
+	}
+	return p.advance()
+}
+
+func (p *parser) skipPunct(value string) (bool, error) {
+	if !p.peekPunct(value) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", fmt.Errorf("%s: expected name, got %s", p.tok.pos, p.tok)
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) expectKeyword(value string) error {
+	if !p.peekName(value) {
+		return fmt.Errorf("%s: expected %q, got %s", p.tok.pos, value, p.tok)
+	}
+	return p.advance()
+}
+
+func (p *parser) parseOperation() (*Operation, error) {
+	op := &Operation{Type: OperationType(p.tok.value), Position: p.tok.pos}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokenName {
+		op.Name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peekPunct("(") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for !p.peekPunct(")") {
+			def, err := p.parseVariableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			op.VariableDefinitions = append(op.VariableDefinitions, def)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.skipDirectives(); err != nil {
+		return nil, err
+	}
+
+	set, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.SelectionSet = set
+
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinition() (*VariableDefinition, error) {
+	def := &VariableDefinition{Position: p.tok.pos}
+	if err := p.expectPunct("$"); err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	def.Name = name
+	if err := p.expectPunct(":"); err != nil {
+		return nil, err
+	}
+	if def.Type, err = p.parseTypeRef(); err != nil {
+		return nil, err
+	}
+	if ok, err := p.skipPunct("="); err != nil {
+		return nil, err
+	} else if ok {
+		if def.DefaultValue, err = p.parseValue(); err != nil {
+			return nil, err
+		}
+	}
+	return def, p.skipDirectives()
+}
+
+func (p *parser) parseFragment() (*Fragment, error) {
+	f := &Fragment{Position: p.tok.pos}
+	if err := p.expectKeyword("fragment"); err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, fmt.Errorf("%s: fragment cannot be named \"on\"", f.Position)
+	}
+	f.Name = name
+	if err := p.expectKeyword("on"); err != nil {
+		return nil, err
+	}
+	if f.TypeCondition, err = p.expectName(); err != nil {
+		return nil, err
+	}
+	if err := p.skipDirectives(); err != nil {
+		return nil, err
+	}
+	if f.SelectionSet, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (p *parser) parseSelectionSet() (SelectionSet, error) {
+	start := p.tok.pos
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	var set SelectionSet
+	for !p.peekPunct("}") {
+		if p.tok.kind == tokenEOF {
+			return nil, fmt.Errorf("%s: unclosed selection set", start)
+		}
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, sel)
+	}
+	if len(set) == 0 {
+		return nil, fmt.Errorf("%s: empty selection set", start)
+	}
+	return set, p.advance()
+}
+
+func (p *parser) parseSelection() (Selection, error) {
+	pos := p.tok.pos
+	if ok, err := p.skipPunct("..."); err != nil {
+		return nil, err
+	} else if ok {
+		if p.tok.kind == tokenName && p.tok.value != "on" {
+			name := p.tok.value
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			return &FragmentSpread{Name: name, Position: pos}, p.skipDirectives()
+		}
+
+		f := &InlineFragment{Position: pos}
+		if p.peekName("on") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			f.TypeCondition = name
+		}
+		if err := p.skipDirectives(); err != nil {
+			return nil, err
+		}
+		set, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		f.SelectionSet = set
+		return f, nil
+	}
+
+	return p.parseField()
+}
+
+func (p *parser) parseField() (*Field, error) {
+	f := &Field{Position: p.tok.pos}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := p.skipPunct(":"); err != nil {
+		return nil, err
+	} else if ok {
+		f.Alias = name
+		if name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+	f.Name = name
+
+	if f.Arguments, err = p.parseArguments(); err != nil {
+		return nil, err
+	}
+	if err := p.skipDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peekPunct("{") {
+		if f.SelectionSet, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (p *parser) parseArguments() ([]*Argument, error) {
+	if !p.peekPunct("(") {
+		return nil, nil
+	}
+	start := p.tok.pos
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	var args []*Argument
+	for !p.peekPunct(")") {
+		if p.tok.kind == tokenEOF {
+			return nil, fmt.Errorf("%s: unclosed argument list", start)
+		}
+		arg := &Argument{Position: p.tok.pos}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		arg.Name = name
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		if arg.Value, err = p.parseValue(); err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, p.advance()
+}
+
+func (p *parser) skipDirectives() error {
+	for p.peekPunct("@") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if _, err := p.expectName(); err != nil {
+			return err
+		}
+		if _, err := p.parseArguments(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseValue() (*Value, error) {
+	v := &Value{Position: p.tok.pos}
+	switch p.tok.kind {
+	case tokenInt:
+		v.Kind, v.Raw = IntValue, p.tok.value
+	case tokenFloat:
+		v.Kind, v.Raw = FloatValue, p.tok.value
+	case tokenString:
+		v.Kind, v.Raw = StringValue, p.tok.value
+	case tokenName:
+		switch p.tok.value {
+		case "true", "false":
+			v.Kind = BooleanValue
+		case "null":
+			v.Kind = NullValue
+		default:
+			v.Kind = EnumValue
+		}
+		v.Raw = p.tok.value
+	case tokenPunct:
+		switch p.tok.value {
+		case "$":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			v.Kind, v.Raw = VariableValue, name
+			return v, nil
+		case "[":
+			v.Kind = ListValue
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			for !p.peekPunct("]") {
+				if p.tok.kind == tokenEOF {
+					return nil, fmt.Errorf("%s: unclosed list value", v.Position)
+				}
+				item, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				v.List = append(v.List, item)
+			}
+		case "{":
+			v.Kind = ObjectValue
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			for !p.peekPunct("}") {
+				if p.tok.kind == tokenEOF {
+					return nil, fmt.Errorf("%s: unclosed object value", v.Position)
+				}
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectPunct(":"); err != nil {
+					return nil, err
+				}
+				fv, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				v.Fields = append(v.Fields, &ObjectField{Name: name, Value: fv})
+			}
+		default:
+			return nil, p.unexpected()
+		}
+	default:
+		return nil, p.unexpected()
+	}
+	return v, p.advance()
+}
+
+func (p *parser) parseTypeRef() (*TypeRef, error) {
+	var t *TypeRef
+	if ok, err := p.skipPunct("["); err != nil {
+		return nil, err
+	} else if ok {
+		elem, err := p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return nil, err
+		}
+		t = &TypeRef{Elem: elem}
+	} else {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		t = &TypeRef{Name: name}
+	}
+	ok, err := p.skipPunct("!")
+	if err != nil {
+		return nil, err
+	}
+	t.NonNull = ok
+	return t, nil
+}
@@ -0,0 +1,599 @@
+// Package schema parses GraphQL documents and validates them against a snapshot of the Shopify Admin API schema.
+//
+// Snapshots are bundled per API version as SDL files. `<version>.graphql` is written by schema/gen from the
+// introspection of the Admin API, with field arguments and the real scalar names. When a version only has
+// `<version>.models.graphql`, the fallback generated from the model package, it is extended with the hand-written
+// `<version>.arguments.graphql`, since the models don't describe arguments. Fields declared there get their arguments
+// validated, other fields only their selections, and scalar names are approximated from the Go types.
+package schema
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The introspection snapshot needs the Admin API access token of a store:
+//
+//	SHOPIFY_ACCESS_TOKEN=... go run ./gen -introspect https://<store>.myshopify.com/admin/api/2023-04/graphql.json -out 2023-04.graphql
+//
+//go:generate go run ./gen -models ../model/models.go -out 2023-04.models.graphql
+
+//go:embed *.graphql
+var snapshots embed.FS
+
+type TypeKind string
+
+const (
+	Scalar      TypeKind = "SCALAR"
+	Object      TypeKind = "OBJECT"
+	Interface   TypeKind = "INTERFACE"
+	Union       TypeKind = "UNION"
+	Enum        TypeKind = "ENUM"
+	InputObject TypeKind = "INPUT_OBJECT"
+)
+
+type Schema struct {
+	QueryType    string
+	MutationType string
+	Types        map[string]*Type
+
+	// partial is set for the fallback snapshots, which only have the arguments of the overlaid fields.
+	partial bool
+}
+
+type Type struct {
+	Name          string
+	Kind          TypeKind
+	Fields        []*FieldDefinition
+	Interfaces    []string
+	PossibleTypes []string
+	EnumValues    []string
+
+	fields map[string]*FieldDefinition
+}
+
+type FieldDefinition struct {
+	Name      string
+	Arguments []*InputValueDefinition
+	Type      *TypeRef
+	// DefaultValue is only set on the fields of input objects.
+	DefaultValue *Value
+}
+
+type InputValueDefinition struct {
+	Name         string
+	Type         *TypeRef
+	DefaultValue *Value
+}
+
+var builtinScalars = []string{"String", "Int", "Float", "Boolean", "ID"}
+
+var (
+	loadedMu sync.Mutex
+	loaded   = make(map[string]*Schema)
+)
+
+const (
+	snapshotExt         = ".graphql"
+	fallbackSnapshotExt = ".models.graphql"
+	argumentsExt        = ".arguments.graphql"
+)
+
+// Versions returns the API versions that have a bundled schema snapshot.
+func Versions() []string {
+	entries, _ := snapshots.ReadDir(".")
+	seen := make(map[string]bool)
+	versions := make([]string, 0, len(entries))
+	for _, e := range entries {
+		version := e.Name()
+		for _, ext := range []string{fallbackSnapshotExt, argumentsExt, snapshotExt} {
+			version = strings.TrimSuffix(version, ext)
+		}
+		if !seen[version] {
+			seen[version] = true
+			versions = append(versions, version)
+		}
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// Load returns the bundled schema snapshot of the given API version, e.g. "2023-04", preferring the introspection
+// snapshot over the fallback generated from the models and extended with the arguments overlay.
+func Load(version string) (*Schema, error) {
+	loadedMu.Lock()
+	defer loadedMu.Unlock()
+
+	if s, ok := loaded[version]; ok {
+		return s, nil
+	}
+
+	partial := false
+	b, err := snapshots.ReadFile(version + snapshotExt)
+	if err != nil {
+		partial = true
+		b, err = snapshots.ReadFile(version + fallbackSnapshotExt)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("no schema snapshot for API version %s", version)
+	}
+	if partial {
+		if args, err := snapshots.ReadFile(version + argumentsExt); err == nil {
+			b = append(append(b, '\n'), args...)
+		}
+	}
+	s, err := ParseSchema(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s schema: %w", version, err)
+	}
+	s.partial = partial
+	loaded[version] = s
+
+	return s, nil
+}
+
+// Partial reports whether the schema is a fallback snapshot, against which only the arguments of the fields declared
+// in its arguments overlay are validated.
+func (s *Schema) Partial() bool {
+	return s.partial
+}
+
+// Type returns the named type or nil.
+func (s *Schema) Type(name string) *Type {
+	return s.Types[name]
+}
+
+// RootType returns the root type of the given operation type or nil.
+func (s *Schema) RootType(op OperationType) *Type {
+	switch op {
+	case OperationQuery:
+		return s.Types[s.QueryType]
+	case OperationMutation:
+		return s.Types[s.MutationType]
+	}
+	return nil
+}
+
+// IsLeaf reports whether the named type is a scalar or an enum.
+func (s *Schema) IsLeaf(name string) bool {
+	t := s.Types[name]
+	return t != nil && (t.Kind == Scalar || t.Kind == Enum)
+}
+
+// PossibleTypes returns the object types a value of the named type can be.
+func (s *Schema) PossibleTypes(name string) []string {
+	t := s.Types[name]
+	if t == nil {
+		return nil
+	}
+	switch t.Kind {
+	case Object:
+		return []string{t.Name}
+	case Interface, Union:
+		return t.PossibleTypes
+	}
+	return nil
+}
+
+// Overlaps reports whether values of type a can also be of type b, the condition for spreading a fragment on b
+// inside a selection on a.
+func (s *Schema) Overlaps(a, b string) bool {
+	if a == b {
+		return true
+	}
+	for _, pa := range s.PossibleTypes(a) {
+		for _, pb := range s.PossibleTypes(b) {
+			if pa == pb {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Field returns the field definition with the given name or nil.
+func (t *Type) Field(name string) *FieldDefinition {
+	return t.fields[name]
+}
+
+// Argument returns the argument definition with the given name or nil.
+func (f *FieldDefinition) Argument(name string) *InputValueDefinition {
+	for _, a := range f.Arguments {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// IsComposite reports whether a selection set can be made on the type.
+func (t *Type) IsComposite() bool {
+	return t.Kind == Object || t.Kind == Interface || t.Kind == Union
+}
+
+func (t *Type) addField(f *FieldDefinition) {
+	if t.fields == nil {
+		t.fields = make(map[string]*FieldDefinition)
+	}
+	if old, ok := t.fields[f.Name]; ok {
+		for i, field := range t.Fields {
+			if field == old {
+				t.Fields[i] = f
+			}
+		}
+	} else {
+		t.Fields = append(t.Fields, f)
+	}
+	t.fields[f.Name] = f
+}
+
+// ParseSchema parses a schema definition document (SDL). Directives are accepted and ignored.
+func ParseSchema(src string) (*Schema, error) {
+	p, err := newParser(src)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Schema{Types: make(map[string]*Type)}
+	for _, name := range builtinScalars {
+		s.Types[name] = &Type{Name: name, Kind: Scalar}
+	}
+
+	for p.tok.kind != tokenEOF {
+		if p.tok.kind == tokenString {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+
+		extend := p.peekName("extend")
+		if extend {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+
+		if p.tok.kind != tokenName {
+			return nil, p.unexpected()
+		}
+		pos := p.tok.pos
+		keyword := p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		switch keyword {
+		case "schema":
+			if err := p.parseSchemaDefinition(s); err != nil {
+				return nil, err
+			}
+			continue
+		case "directive":
+			if err := p.skipDirectiveDefinition(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		t := s.Types[name]
+		if t == nil {
+			t = &Type{Name: name}
+			s.Types[name] = t
+		} else if !extend && t.Kind != Scalar {
+			return nil, fmt.Errorf("%s: type %s is defined more than once", pos, name)
+		}
+
+		switch keyword {
+		case "scalar":
+			t.Kind = Scalar
+			err = p.skipDirectives()
+		case "type", "interface":
+			t.Kind = Object
+			if keyword == "interface" {
+				t.Kind = Interface
+			}
+			err = p.parseObjectDefinition(t)
+		case "input":
+			t.Kind = InputObject
+			err = p.parseInputDefinition(t)
+		case "union":
+			t.Kind = Union
+			err = p.parseUnionDefinition(t)
+		case "enum":
+			t.Kind = Enum
+			err = p.parseEnumDefinition(t)
+		default:
+			err = fmt.Errorf("%s: unexpected %q", pos, keyword)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.QueryType == "" {
+		s.QueryType = "Query"
+	}
+	if s.MutationType == "" {
+		s.MutationType = "Mutation"
+	}
+
+	return s, s.link()
+}
+
+// link resolves possible types of interfaces and checks that every referenced type is defined.
+func (s *Schema) link() error {
+	names := make([]string, 0, len(s.Types))
+	for name := range s.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		t := s.Types[name]
+		if t.Kind != Object {
+			continue
+		}
+		for _, i := range t.Interfaces {
+			it := s.Types[i]
+			if it == nil || it.Kind != Interface {
+				return fmt.Errorf("type %s implements undefined interface %s", name, i)
+			}
+			it.PossibleTypes = append(it.PossibleTypes, name)
+		}
+	}
+
+	for _, name := range names {
+		t := s.Types[name]
+		for _, f := range t.Fields {
+			if s.Types[f.Type.NamedType()] == nil {
+				return fmt.Errorf("field %s.%s has undefined type %s", name, f.Name, f.Type.NamedType())
+			}
+			for _, a := range f.Arguments {
+				if s.Types[a.Type.NamedType()] == nil {
+					return fmt.Errorf("argument %s of %s.%s has undefined type %s", a.Name, name, f.Name, a.Type.NamedType())
+				}
+			}
+		}
+		for _, pt := range t.PossibleTypes {
+			if s.Types[pt] == nil {
+				return fmt.Errorf("union %s has undefined member %s", name, pt)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) parseSchemaDefinition(s *Schema) error {
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if err := p.expectPunct("{"); err != nil {
+		return err
+	}
+	for !p.peekPunct("}") {
+		op, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		switch OperationType(op) {
+		case OperationQuery:
+			s.QueryType = name
+		case OperationMutation:
+			s.MutationType = name
+		}
+	}
+	return p.advance()
+}
+
+func (p *parser) skipDirectiveDefinition() error {
+	if err := p.expectPunct("@"); err != nil {
+		return err
+	}
+	if _, err := p.expectName(); err != nil {
+		return err
+	}
+	if p.peekPunct("(") {
+		if _, err := p.parseInputValueDefinitions("(", ")"); err != nil {
+			return err
+		}
+	}
+	if p.peekName("repeatable") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+	if err := p.expectKeyword("on"); err != nil {
+		return err
+	}
+	if _, err := p.skipPunct("|"); err != nil {
+		return err
+	}
+	for {
+		if _, err := p.expectName(); err != nil {
+			return err
+		}
+		if ok, err := p.skipPunct("|"); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseObjectDefinition(t *Type) error {
+	if p.peekName("implements") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if _, err := p.skipPunct("&"); err != nil {
+			return err
+		}
+		for p.tok.kind == tokenName {
+			t.Interfaces = append(t.Interfaces, p.tok.value)
+			if err := p.advance(); err != nil {
+				return err
+			}
+			if _, err := p.skipPunct("&"); err != nil {
+				return err
+			}
+		}
+	}
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if !p.peekPunct("{") {
+		return nil
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+	for !p.peekPunct("}") {
+		if p.tok.kind == tokenString {
+			if err := p.advance(); err != nil {
+				return err
+			}
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		f := &FieldDefinition{Name: name}
+		if p.peekPunct("(") {
+			if f.Arguments, err = p.parseInputValueDefinitions("(", ")"); err != nil {
+				return err
+			}
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return err
+		}
+		if f.Type, err = p.parseTypeRef(); err != nil {
+			return err
+		}
+		if err := p.skipDirectives(); err != nil {
+			return err
+		}
+		t.addField(f)
+	}
+	return p.advance()
+}
+
+func (p *parser) parseInputDefinition(t *Type) error {
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if !p.peekPunct("{") {
+		return nil
+	}
+	defs, err := p.parseInputValueDefinitions("{", "}")
+	if err != nil {
+		return err
+	}
+	for _, d := range defs {
+		t.addField(&FieldDefinition{Name: d.Name, Type: d.Type, DefaultValue: d.DefaultValue})
+	}
+	return nil
+}
+
+func (p *parser) parseInputValueDefinitions(open, close string) ([]*InputValueDefinition, error) {
+	if err := p.expectPunct(open); err != nil {
+		return nil, err
+	}
+	var defs []*InputValueDefinition
+	for !p.peekPunct(close) {
+		if p.tok.kind == tokenString {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		d := &InputValueDefinition{Name: name}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		if d.Type, err = p.parseTypeRef(); err != nil {
+			return nil, err
+		}
+		if ok, err := p.skipPunct("="); err != nil {
+			return nil, err
+		} else if ok {
+			if d.DefaultValue, err = p.parseValue(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.skipDirectives(); err != nil {
+			return nil, err
+		}
+		defs = append(defs, d)
+	}
+	return defs, p.advance()
+}
+
+func (p *parser) parseUnionDefinition(t *Type) error {
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if ok, err := p.skipPunct("="); err != nil || !ok {
+		return err
+	}
+	if _, err := p.skipPunct("|"); err != nil {
+		return err
+	}
+	for {
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		t.PossibleTypes = append(t.PossibleTypes, name)
+		if ok, err := p.skipPunct("|"); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseEnumDefinition(t *Type) error {
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if !p.peekPunct("{") {
+		return nil
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+	for !p.peekPunct("}") {
+		if p.tok.kind == tokenString {
+			if err := p.advance(); err != nil {
+				return err
+			}
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		t.EnumValues = append(t.EnumValues, name)
+		if err := p.skipDirectives(); err != nil {
+			return err
+		}
+	}
+	return p.advance()
+}
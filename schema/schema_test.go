@@ -0,0 +1,314 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/sogko/go-shopify-graphql/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateQuery(t *testing.T) {
+	s, err := schema.Load("2023-04")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		query string
+		err   string
+	}{{
+		name: "valid",
+		query: `
+			query product($id: ID!) {
+				product(id: $id){
+					id
+					title
+					variants(first: 10){
+						edges{
+							node{
+								id
+								sku
+							}
+						}
+					}
+					media(first: 5){
+						edges{
+							node{
+								... on MediaImage {
+									id
+								}
+							}
+						}
+					}
+				}
+			}`,
+	}, {
+		name:  "unknown field",
+		query: `{ products{ edges{ node{ id titel } } } }`,
+		err:   "1:29: products.edges.node.titel: field `titel` is not defined on type `Product`",
+	}, {
+		name:  "selection on leaf",
+		query: `{ shop{ name{ value } } }`,
+		err:   "1:9: shop.name: field `name` of leaf type `String!` must not have a selection",
+	}, {
+		name:  "missing selection",
+		query: `{ shop }`,
+		err:   "1:3: shop: field `shop` of type `Shop` must have a selection of subfields",
+	}, {
+		name:  "undefined variable",
+		query: `query { product(id: $id){ id } }`,
+		err:   "1:17: variable `$id` is not defined by operation",
+	}, {
+		name:  "unused variable",
+		query: `query($id: ID!, $cursor: String) { product(id: $id){ id } }`,
+		err:   "1:17: variable `$cursor` is never used",
+	}, {
+		name:  "impossible fragment",
+		query: `{ products{ edges{ node{ ... on Order { id } } } } }`,
+		err:   "1:26: products.edges.node: fragment cannot be spread here as objects of type `Product` can never be of type `Order`",
+	}, {
+		name:  "undefined fragment",
+		query: `{ products{ edges{ node{ ...product } } } }`,
+		err:   "1:26: products.edges.node: fragment `product` is not defined",
+	}, {
+		name:  "unknown argument",
+		query: `{ products(frist: 10){ edges{ node{ id } } } }`,
+		err:   "1:12: products: unknown argument `frist` on field `QueryRoot.products`",
+	}, {
+		name:  "unknown argument on nested field",
+		query: `query($id: ID!) { product(id: $id){ variants(first: 10, sort: POSITION){ edges{ node{ id } } } } }`,
+		err:   "1:57: product.variants: unknown argument `sort` on field `Product.variants`",
+	}, {
+		name:  "missing required argument",
+		query: `{ productByHandle{ id } }`,
+		err:   "1:3: productByHandle: field `QueryRoot.productByHandle` is missing the required argument `handle` of type `String!`",
+	}, {
+		name:  "wrong argument type",
+		query: `{ products(first: 10, sortKey: PRICE){ edges{ node{ id } } } }`,
+		err:   "1:23: products: argument `sortKey` expects a value of the enum `ProductSortKeys`, got the enum value PRICE",
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.ValidateQuery(tt.query)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	doc, err := schema.ParseQuery(`
+		query orders($query: String) {
+			orders(query: $query, first: 10){
+				edges{
+					node{
+						...order
+					}
+				}
+			}
+		}
+
+		fragment order on Order {
+			id
+			name
+		}`)
+	require.NoError(t, err)
+	require.Len(t, doc.Operations, 1)
+	require.Len(t, doc.Fragments, 1)
+
+	op := doc.Operations[0]
+	assert.Equal(t, schema.OperationQuery, op.Type)
+	assert.Equal(t, "orders", op.Name)
+	assert.Equal(t, "String", op.VariableDefinitions[0].Type.String())
+
+	orders := op.SelectionSet[0].(*schema.Field)
+	assert.Equal(t, "orders", orders.Name)
+	assert.Equal(t, []string{"query"}, orders.Argument("query").Value.Variables())
+	assert.Equal(t, "10", orders.Argument("first").Value.Raw)
+
+	_, err = schema.ParseQuery(`{ products{ edges{ node{ id } }`)
+	assert.EqualError(t, err, "1:11: unclosed selection set")
+}
+
+func TestValidateBulkQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		err   string
+	}{{
+		name: "valid",
+		query: `
+			{
+				products{
+					edges{
+						node{
+							...product
+							variants{
+								edges{
+									node{
+										id
+										media{
+											edges{
+												node{
+													... on MediaImage {
+														id
+													}
+												}
+											}
+										}
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+
+			fragment product on Product {
+				id
+				title
+			}`,
+	}, {
+		name:  "mutation",
+		query: `mutation { productDelete(input: {id: "1"}){ deletedProductId } }`,
+		err:   "1:1: a bulk query must be a query, got a mutation",
+	}, {
+		name:  "top level node",
+		query: `{ node(id: "gid://shopify/Product/1"){ id } }`,
+		err:   "1:3: node: bulk queries don't support the top level `node` field, query a connection instead",
+	}, {
+		name:  "no connection",
+		query: `{ shop{ name } }`,
+		err:   "1:3: a bulk query must query a connection",
+	}, {
+		name:  "nested node without id",
+		query: `{ products{ edges{ node{ id variants{ edges{ node{ sku } } } } } } }`,
+		err:   "1:46: products.edges.node.variants.edges.node: the nodes of nested connections must select the `id` field to be linked to their parent",
+	}, {
+		name:  "parent node without id",
+		query: `{ products{ edges{ node{ title variants{ edges{ node{ id } } } } } } }`,
+		err:   "1:20: products.edges.node: the nodes of connections with nested connections must select the `id` field to be linked to their children",
+	}, {
+		name: "too deep",
+		query: `{ products{ edges{ node{ id collections{ edges{ node{ id products{ edges{ node{ id
+			variants{ edges{ node{ id } } } } } } } } } } } } }`,
+		err: "2:4: products.edges.node.collections.edges.node.products.edges.node.variants: bulk queries can nest connections at most 2 levels deep",
+	}, {
+		name: "too many connections",
+		query: `{ products{ edges{ node{ id
+			a: metafields{ edges{ node{ id } } }
+			b: metafields{ edges{ node{ id } } }
+			c: metafields{ edges{ node{ id } } }
+			d: metafields{ edges{ node{ id } } }
+			e: metafields{ edges{ node{ id } } }
+		} } } }`,
+		err: "6:4: products.edges.node.e: bulk queries can hold at most 5 connections",
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.ValidateBulkQuery(tt.query)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+const argumentsSchema = `
+schema {
+	query: QueryRoot
+	mutation: Mutation
+}
+
+scalar DateTime
+
+enum ProductSortKeys {
+	ID
+	TITLE
+}
+
+input ProductVariantsBulkDeleteInput {
+	productId: ID!
+	variantsIds: [ID!]!
+	reason: String = "manual"
+}
+
+type Product {
+	id: ID!
+	updatedAt: DateTime!
+}
+
+type ProductConnection {
+	nodes: [Product!]!
+}
+
+type QueryRoot {
+	product(id: ID!): Product
+	products(first: Int, after: String, reverse: Boolean = false, sortKey: ProductSortKeys = ID, query: String): ProductConnection!
+}
+
+type Mutation {
+	productVariantsBulkDelete(productId: ID!, variantsIds: [ID!]!): Product
+	bulkDelete(input: ProductVariantsBulkDeleteInput!): Product
+}
+`
+
+func TestValidateArguments(t *testing.T) {
+	s, err := schema.ParseSchema(argumentsSchema)
+	require.NoError(t, err)
+	assert.False(t, s.Partial())
+
+	tests := []struct {
+		name  string
+		query string
+		err   string
+	}{{
+		name:  "valid",
+		query: `mutation($id: ID!, $ids: [ID!]!) { productVariantsBulkDelete(productId: $id, variantsIds: $ids){ id } }`,
+	}, {
+		name:  "valid literals",
+		query: `{ products(first: 10, reverse: true, sortKey: TITLE, query: "tag:a"){ nodes{ id } } }`,
+	}, {
+		name:  "misspelled argument",
+		query: `mutation($id: ID!, $ids: [ID!]!) { productVariantsBulkDelete(productId: $id, variantIds: $ids){ id } }`,
+		err: "1:78: productVariantsBulkDelete: unknown argument `variantIds` on field `Mutation.productVariantsBulkDelete`\n" +
+			"1:36: productVariantsBulkDelete: field `Mutation.productVariantsBulkDelete` is missing the required argument `variantsIds` of type `[ID!]!`",
+	}, {
+		name:  "wrong literal",
+		query: `{ products(first: "ten", sortKey: PRICE){ nodes{ id } } }`,
+		err: "1:12: products: argument `first` expects a `Int`, got the string \"ten\"\n" +
+			"1:26: products: argument `sortKey` expects a value of the enum `ProductSortKeys`, got the enum value PRICE",
+	}, {
+		name:  "wrong variable type",
+		query: `query($first: String, $id: ID) { products(first: $first){ nodes{ id } } product(id: $id){ id } }`,
+		err: "1:43: products: variable `$first` of type `String` is used for argument `first` of type `Int`\n" +
+			"1:81: product: variable `$id` of type `ID` is used for argument `id` of type `ID!`",
+	}, {
+		name:  "input object",
+		query: `mutation { bulkDelete(input: {productId: "1", variantIds: ["2"]}){ id } }`,
+		err: "1:23: bulkDelete: argument `input` has the unknown field `variantIds` of the input object `ProductVariantsBulkDeleteInput`\n" +
+			"1:23: bulkDelete: argument `input` is missing the required field `variantsIds` of the input object `ProductVariantsBulkDeleteInput`",
+	}, {
+		name:  "argument on a field without arguments",
+		query: `{ product(id: "1"){ updatedAt(format: "iso") } }`,
+		err:   "1:31: product.updatedAt: unknown argument `format` on field `Product.updatedAt`",
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.ValidateQuery(tt.query)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
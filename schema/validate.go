@@ -0,0 +1,625 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/vinhluan/go-graphql-client/ident"
+)
+
+// Error is a single validation failure, located by its position in the document (if any) and the path of response
+// keys leading to it.
+type Error struct {
+	Message  string
+	Position Position
+	Path     []string
+}
+
+func (e *Error) Error() string {
+	var sb strings.Builder
+	if e.Position.Line > 0 {
+		sb.WriteString(e.Position.String())
+		sb.WriteString(": ")
+	}
+	if len(e.Path) > 0 {
+		sb.WriteString(strings.Join(e.Path, "."))
+		sb.WriteString(": ")
+	}
+	sb.WriteString(e.Message)
+	return sb.String()
+}
+
+// Errors is the list of all validation failures of a document.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+type validator struct {
+	schema *Schema
+	doc    *QueryDocument
+	errs   Errors
+
+	// varDefs holds the variable definitions of the operation being validated, nil outside of an operation.
+	varDefs map[string]*VariableDefinition
+}
+
+func (v *validator) errorf(pos Position, path []string, format string, args ...interface{}) {
+	v.errs = append(v.errs, &Error{
+		Message:  fmt.Sprintf(format, args...),
+		Position: pos,
+		Path:     append([]string(nil), path...),
+	})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// ValidateQuery parses and validates an executable document.
+func (s *Schema) ValidateQuery(src string) error {
+	doc, err := ParseQuery(src)
+	if err != nil {
+		return err
+	}
+	return s.Validate(doc)
+}
+
+// Validate checks that every selection in the document exists in the schema, that leaf fields have no selection
+// while object fields do, that fragments are defined, used and applicable where they are spread, and that operations
+// define exactly the variables they use. It also checks that fields get their required arguments and only known ones,
+// of the expected types, which for a Partial schema is limited to the fields that declare arguments. The returned
+// error is of type Errors.
+func (s *Schema) Validate(doc *QueryDocument) error {
+	v := &validator{schema: s, doc: doc}
+
+	anonymous := 0
+	opNames := make(map[string]bool)
+	for _, op := range doc.Operations {
+		if op.Name == "" {
+			anonymous++
+		} else if opNames[op.Name] {
+			v.errorf(op.Position, nil, "operation `%s` is defined more than once", op.Name)
+		}
+		opNames[op.Name] = true
+		v.validateOperation(op)
+	}
+	if anonymous > 0 && len(doc.Operations) > 1 {
+		v.errorf(Position{}, nil, "anonymous operation must be the only defined operation")
+	}
+
+	used := make(map[string]bool)
+	for _, op := range doc.Operations {
+		v.collectSpreads(op.SelectionSet, used)
+	}
+
+	fragmentNames := make(map[string]bool)
+	for _, f := range doc.Fragments {
+		if fragmentNames[f.Name] {
+			v.errorf(f.Position, nil, "fragment `%s` is defined more than once", f.Name)
+		}
+		fragmentNames[f.Name] = true
+
+		if len(doc.Operations) > 0 && !used[f.Name] {
+			v.errorf(f.Position, nil, "fragment `%s` is never used", f.Name)
+		}
+
+		t := s.Type(f.TypeCondition)
+		if t == nil {
+			v.errorf(f.Position, nil, "fragment `%s` is on unknown type `%s`", f.Name, f.TypeCondition)
+			continue
+		}
+		if !t.IsComposite() {
+			v.errorf(f.Position, nil, "fragment `%s` cannot condition on non composite type `%s`", f.Name, f.TypeCondition)
+			continue
+		}
+		v.validateSelectionSet(t, f.SelectionSet, []string{f.Name})
+	}
+
+	return v.err()
+}
+
+// ValidateSelection parses and validates a bare selection set, as used in query building blocks, on the named type.
+// Variables are not checked since the selection is not part of an operation.
+func (s *Schema) ValidateSelection(typeName string, src string) error {
+	set, err := ParseSelectionSet(src)
+	if err != nil {
+		return err
+	}
+
+	v := &validator{schema: s, doc: &QueryDocument{}}
+	t := s.Type(typeName)
+	if t == nil || !t.IsComposite() {
+		v.errorf(Position{}, nil, "`%s` is not an object, interface or union type", typeName)
+		return v.err()
+	}
+	v.validateSelectionSet(t, set, nil)
+
+	return v.err()
+}
+
+func (v *validator) validateOperation(op *Operation) {
+	root := v.schema.RootType(op.Type)
+	if root == nil {
+		v.errorf(op.Position, nil, "schema does not support %s operations", op.Type)
+		return
+	}
+	v.varDefs = make(map[string]*VariableDefinition, len(op.VariableDefinitions))
+	for _, def := range op.VariableDefinitions {
+		v.varDefs[def.Name] = def
+	}
+	v.validateSelectionSet(root, op.SelectionSet, nil)
+	v.varDefs = nil
+
+	defined := make(map[string]bool)
+	for _, def := range op.VariableDefinitions {
+		if defined[def.Name] {
+			v.errorf(def.Position, nil, "variable `$%s` is defined more than once", def.Name)
+		}
+		defined[def.Name] = true
+		if v.schema.Type(def.Type.NamedType()) == nil {
+			v.errorf(def.Position, nil, "variable `$%s` has unknown type `%s`", def.Name, def.Type.NamedType())
+		}
+	}
+
+	used := make(map[string]Position)
+	v.collectVariables(op.SelectionSet, used, make(map[string]bool))
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !defined[name] {
+			v.errorf(used[name], nil, "variable `$%s` is not defined by operation", name)
+		}
+	}
+	for _, def := range op.VariableDefinitions {
+		if _, ok := used[def.Name]; !ok {
+			v.errorf(def.Position, nil, "variable `$%s` is never used", def.Name)
+		}
+	}
+}
+
+func (v *validator) validateSelectionSet(t *Type, set SelectionSet, path []string) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *Field:
+			v.validateField(t, sel, path)
+		case *InlineFragment:
+			cond := sel.TypeCondition
+			if cond == "" {
+				cond = t.Name
+			}
+			ct := v.fragmentType(t, cond, sel.Position, path)
+			if ct != nil {
+				v.validateSelectionSet(ct, sel.SelectionSet, path)
+			}
+		case *FragmentSpread:
+			f := v.doc.Fragment(sel.Name)
+			if f == nil {
+				v.errorf(sel.Position, path, "fragment `%s` is not defined", sel.Name)
+				continue
+			}
+			v.fragmentType(t, f.TypeCondition, sel.Position, path)
+		}
+	}
+}
+
+// fragmentType returns the type of a fragment condition if the fragment can be spread within a selection on t.
+func (v *validator) fragmentType(t *Type, cond string, pos Position, path []string) *Type {
+	ct := v.schema.Type(cond)
+	if ct == nil {
+		v.errorf(pos, path, "fragment condition on unknown type `%s`", cond)
+		return nil
+	}
+	if !ct.IsComposite() {
+		v.errorf(pos, path, "fragment cannot condition on non composite type `%s`", cond)
+		return nil
+	}
+	if !v.schema.Overlaps(t.Name, ct.Name) {
+		v.errorf(pos, path, "fragment cannot be spread here as objects of type `%s` can never be of type `%s`", t.Name, ct.Name)
+		return nil
+	}
+	return ct
+}
+
+func (v *validator) validateField(t *Type, f *Field, path []string) {
+	path = append(path, f.ResponseKey())
+
+	if f.Name == "__typename" {
+		if len(f.SelectionSet) > 0 {
+			v.errorf(f.Position, path, "field `__typename` of type `String` must not have a selection")
+		}
+		return
+	}
+
+	def := t.Field(f.Name)
+	if def == nil {
+		if t.Kind == Union {
+			v.errorf(f.Position, path, "cannot query field `%s` on union type `%s`, use an inline fragment", f.Name, t.Name)
+		} else {
+			v.errorf(f.Position, path, "field `%s` is not defined on type `%s`", f.Name, t.Name)
+		}
+		return
+	}
+
+	v.validateArguments(t, def, f, path)
+
+	ft := v.schema.Type(def.Type.NamedType())
+	switch {
+	case ft.IsComposite() && len(f.SelectionSet) == 0:
+		v.errorf(f.Position, path, "field `%s` of type `%s` must have a selection of subfields", f.Name, def.Type)
+	case !ft.IsComposite() && len(f.SelectionSet) > 0:
+		v.errorf(f.Position, path, "field `%s` of leaf type `%s` must not have a selection", f.Name, def.Type)
+	case ft.IsComposite():
+		v.validateSelectionSet(ft, f.SelectionSet, path)
+	}
+}
+
+// validateArguments checks the arguments of the field f against its definition def on t. Fallback snapshots only
+// define the arguments of the overlaid fields, so the arguments of the others are left unchecked.
+func (v *validator) validateArguments(t *Type, def *FieldDefinition, f *Field, path []string) {
+	if v.schema.partial && len(def.Arguments) == 0 {
+		return
+	}
+	for _, arg := range f.Arguments {
+		a := def.Argument(arg.Name)
+		if a == nil {
+			v.errorf(arg.Position, path, "unknown argument `%s` on field `%s.%s`", arg.Name, t.Name, f.Name)
+			continue
+		}
+		v.validateValue(arg.Value, a.Type, arg.Position, path, fmt.Sprintf("argument `%s`", arg.Name))
+	}
+	for _, a := range def.Arguments {
+		if a.Type.NonNull && a.DefaultValue == nil && f.Argument(a.Name) == nil {
+			v.errorf(f.Position, path, "field `%s.%s` is missing the required argument `%s` of type `%s`", t.Name, f.Name, a.Name, a.Type)
+		}
+	}
+}
+
+// validateValue checks that the value can be passed where a value of type ref is expected, what describing it.
+func (v *validator) validateValue(val *Value, ref *TypeRef, pos Position, path []string, what string) {
+	if val.Kind == VariableValue {
+		def, ok := v.varDefs[val.Raw]
+		if ok && !variableFits(def.Type, def.DefaultValue != nil, ref) {
+			v.errorf(pos, path, "variable `$%s` of type `%s` is used for %s of type `%s`", val.Raw, def.Type, what, ref)
+		}
+		return
+	}
+	if val.Kind == NullValue {
+		if ref.NonNull {
+			v.errorf(pos, path, "%s of type `%s` can't be null", what, ref)
+		}
+		return
+	}
+	if ref.IsList() {
+		if val.Kind != ListValue {
+			// A single value is coerced to a list of one.
+			v.validateValue(val, ref.Elem, pos, path, what)
+			return
+		}
+		for _, item := range val.List {
+			v.validateValue(item, ref.Elem, pos, path, what)
+		}
+		return
+	}
+
+	t := v.schema.Type(ref.Name)
+	if t == nil {
+		return
+	}
+	switch t.Kind {
+	case Enum:
+		if val.Kind != EnumValue || !containsName(t.EnumValues, val.Raw) {
+			v.errorf(pos, path, "%s expects a value of the enum `%s`, got %s", what, t.Name, describeValue(val))
+		}
+	case InputObject:
+		if val.Kind != ObjectValue {
+			v.errorf(pos, path, "%s expects an input object `%s`, got %s", what, t.Name, describeValue(val))
+			return
+		}
+		for _, field := range val.Fields {
+			fd := t.Field(field.Name)
+			if fd == nil {
+				v.errorf(pos, path, "%s has the unknown field `%s` of the input object `%s`", what, field.Name, t.Name)
+				continue
+			}
+			v.validateValue(field.Value, fd.Type, pos, path, fmt.Sprintf("%s.%s", what, field.Name))
+		}
+		for _, fd := range t.Fields {
+			if fd.Type.NonNull && fd.DefaultValue == nil && objectField(val, fd.Name) == nil {
+				v.errorf(pos, path, "%s is missing the required field `%s` of the input object `%s`", what, fd.Name, t.Name)
+			}
+		}
+	case Scalar:
+		if !scalarAccepts(t.Name, val.Kind) {
+			v.errorf(pos, path, "%s expects a `%s`, got %s", what, t.Name, describeValue(val))
+		}
+	default:
+		v.errorf(pos, path, "%s has the output type `%s`", what, t.Name)
+	}
+}
+
+// variableFits reports whether a variable of type varType can be used where a value of type ref is expected. A
+// nullable variable fits a non-null location when it has a default value.
+func variableFits(varType *TypeRef, hasDefault bool, ref *TypeRef) bool {
+	if ref.NonNull && !varType.NonNull && !hasDefault {
+		return false
+	}
+	if ref.IsList() != varType.IsList() {
+		return false
+	}
+	if ref.IsList() {
+		return variableFits(varType.Elem, false, ref.Elem)
+	}
+	return varType.Name == ref.Name
+}
+
+// scalarAccepts reports whether a literal of the given kind is a valid input for the scalar. Custom scalars, e.g.
+// `DateTime`, `Decimal` or `JSON`, take any literal.
+func scalarAccepts(scalar string, kind ValueKind) bool {
+	switch scalar {
+	case "Int":
+		return kind == IntValue
+	case "Float":
+		return kind == IntValue || kind == FloatValue
+	case "String":
+		return kind == StringValue
+	case "Boolean":
+		return kind == BooleanValue
+	case "ID":
+		return kind == StringValue || kind == IntValue
+	}
+	return kind != EnumValue
+}
+
+func describeValue(val *Value) string {
+	switch val.Kind {
+	case IntValue, FloatValue:
+		return "the number " + val.Raw
+	case StringValue:
+		return fmt.Sprintf("the string %q", val.Raw)
+	case BooleanValue:
+		return "the boolean " + val.Raw
+	case EnumValue:
+		return "the enum value " + val.Raw
+	case ListValue:
+		return "a list"
+	case ObjectValue:
+		return "an object"
+	}
+	return "null"
+}
+
+func objectField(val *Value, name string) *ObjectField {
+	for _, f := range val.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) collectSpreads(set SelectionSet, used map[string]bool) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *Field:
+			v.collectSpreads(sel.SelectionSet, used)
+		case *InlineFragment:
+			v.collectSpreads(sel.SelectionSet, used)
+		case *FragmentSpread:
+			if used[sel.Name] {
+				continue
+			}
+			used[sel.Name] = true
+			if f := v.doc.Fragment(sel.Name); f != nil {
+				v.collectSpreads(f.SelectionSet, used)
+			}
+		}
+	}
+}
+
+func (v *validator) collectVariables(set SelectionSet, used map[string]Position, visited map[string]bool) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *Field:
+			for _, arg := range sel.Arguments {
+				for _, name := range arg.Value.Variables() {
+					if _, ok := used[name]; !ok {
+						used[name] = arg.Position
+					}
+				}
+			}
+			v.collectVariables(sel.SelectionSet, used, visited)
+		case *InlineFragment:
+			v.collectVariables(sel.SelectionSet, used, visited)
+		case *FragmentSpread:
+			if visited[sel.Name] {
+				continue
+			}
+			visited[sel.Name] = true
+			if f := v.doc.Fragment(sel.Name); f != nil {
+				v.collectVariables(f.SelectionSet, used, visited)
+			}
+		}
+	}
+}
+
+var jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+type structVisit struct {
+	goType     reflect.Type
+	schemaType string
+}
+
+type structValidator struct {
+	*validator
+	seen      map[structVisit]bool
+	stack     []reflect.Type
+	variables map[string]bool
+}
+
+// ValidateStruct validates the document that the graphql client derives from the struct v (a pointer to a struct
+// passed to Query or Mutate) against the root type of op. Field names are taken from `graphql` tags or derived from
+// the Go field names the same way the client does, and `$variables` referenced in tags must be provided in variables.
+func (s *Schema) ValidateStruct(op OperationType, v interface{}, variables map[string]interface{}) error {
+	sv := &structValidator{
+		validator: &validator{schema: s, doc: &QueryDocument{}},
+		seen:      make(map[structVisit]bool),
+		variables: make(map[string]bool),
+	}
+
+	root := s.RootType(op)
+	if root == nil {
+		sv.errorf(Position{}, nil, "schema does not support %s operations", op)
+		return sv.err()
+	}
+
+	sv.validateStruct(reflect.TypeOf(v), root, nil)
+
+	names := make([]string, 0, len(sv.variables))
+	for name := range sv.variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := variables[name]; !ok {
+			sv.errorf(Position{}, nil, "variable `$%s` is used but not provided", name)
+		}
+	}
+	for name := range variables {
+		if !sv.variables[name] {
+			sv.errorf(Position{}, nil, "variable `$%s` is provided but never used", name)
+		}
+	}
+
+	return sv.err()
+}
+
+// baseType strips pointers, slices and arrays the same way the client does when writing a query.
+func baseType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
+
+func isGoComposite(t reflect.Type) bool {
+	t = baseType(t)
+	return t.Kind() == reflect.Struct && !reflect.PtrTo(t).Implements(jsonUnmarshaler)
+}
+
+func (v *structValidator) onStack(t reflect.Type) bool {
+	for _, s := range v.stack {
+		if s == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *structValidator) validateStruct(gt reflect.Type, t *Type, path []string) {
+	gt = baseType(gt)
+	if !isGoComposite(gt) {
+		return
+	}
+
+	key := structVisit{goType: gt, schemaType: t.Name}
+	if v.seen[key] {
+		return
+	}
+	v.seen[key] = true
+	v.stack = append(v.stack, gt)
+	defer func() { v.stack = v.stack[:len(v.stack)-1] }()
+
+	for i := 0; i < gt.NumField(); i++ {
+		field := gt.Field(i)
+		if v.onStack(baseType(field.Type)) {
+			// The client skips fields that would recurse into a type being written.
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup("graphql")
+		if field.Anonymous && !ok {
+			v.validateStruct(field.Type, t, path)
+			continue
+		}
+
+		if ok && strings.HasPrefix(strings.TrimSpace(tag), "...") {
+			sel, err := ParseSelectionSet(tag + "{__typename}")
+			if err != nil {
+				v.errorf(Position{}, path, "invalid tag on %s.%s: %s", gt.Name(), field.Name, err)
+				continue
+			}
+			frag := sel[0].(*InlineFragment)
+			if frag.TypeCondition == "" {
+				frag.TypeCondition = t.Name
+			}
+			if ct := v.fragmentType(t, frag.TypeCondition, Position{}, path); ct != nil {
+				v.validateStruct(field.Type, ct, path)
+			}
+			continue
+		}
+
+		f := &Field{Name: ident.ParseMixedCaps(field.Name).ToLowerCamelCase()}
+		if ok {
+			sel, err := ParseSelectionSet(tag)
+			if err != nil || len(sel) != 1 {
+				v.errorf(Position{}, path, "invalid tag on %s.%s: %q", gt.Name(), field.Name, tag)
+				continue
+			}
+			var isField bool
+			if f, isField = sel[0].(*Field); !isField {
+				v.errorf(Position{}, path, "invalid tag on %s.%s: %q", gt.Name(), field.Name, tag)
+				continue
+			}
+			for _, arg := range f.Arguments {
+				for _, name := range arg.Value.Variables() {
+					v.variables[name] = true
+				}
+			}
+		}
+
+		fieldPath := append(path, f.ResponseKey())
+		if f.Name == "__typename" {
+			continue
+		}
+		def := t.Field(f.Name)
+		if def == nil {
+			v.errorf(Position{}, fieldPath, "field `%s` (%s.%s) is not defined on type `%s`", f.Name, gt.Name(), field.Name, t.Name)
+			continue
+		}
+		v.validateArguments(t, def, f, path)
+
+		ft := v.schema.Type(def.Type.NamedType())
+		switch composite := isGoComposite(field.Type); {
+		case ft.IsComposite() && !composite:
+			v.errorf(Position{}, fieldPath, "field `%s` of type `%s` must have a selection of subfields, but %s.%s is a %s", f.Name, def.Type, gt.Name(), field.Name, field.Type)
+		case !ft.IsComposite() && composite:
+			v.errorf(Position{}, fieldPath, "field `%s` of leaf type `%s` must not have a selection, but %s.%s is a %s", f.Name, def.Type, gt.Name(), field.Name, field.Type)
+		case ft.IsComposite():
+			v.validateStruct(field.Type, ft, fieldPath)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package shopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/sogko/go-shopify-graphql/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vinhluan/go-graphql-client"
+)
+
+var errRecorded = errors.New("recorded")
+
+// recordingBulkOperationService records the bulk queries the services post.
+type recordingBulkOperationService struct {
+	BulkOperationService
+	queries []string
+}
+
+func (s *recordingBulkOperationService) BulkQuery(ctx context.Context, query string, out interface{}, opts ...BulkQueryOption) error {
+	s.queries = append(s.queries, query)
+	return errRecorded
+}
+
+// recordedOperation is an operation sent by the call name, a bulk query when bulk is set.
+type recordedOperation struct {
+	queuedOperation
+	name string
+	bulk bool
+}
+
+func TestBuiltInDocumentsMatchSchema(t *testing.T) {
+	s, err := schema.Load(defaultShopifyAPIVersion)
+	require.NoError(t, err)
+
+	// Every operation fails, so no service gets past its first call.
+	gql := &queuedGraphQL{err: errRecorded}
+	bulk := &recordingBulkOperationService{}
+	client := NewClient("test", WithGraphQLClient(gql), WithVersion(defaultShopifyAPIVersion))
+	client.BulkOperation = bulk
+	bulkClient := NewClient("test", WithGraphQLClient(gql))
+
+	ctx := context.Background()
+	calls := map[string]func(){
+		"Product.List": func() { client.Product.List(ctx, `title:"x") { id } #`) },
+		"Product.ListPage": func() {
+			client.Product.ListPage(ctx, ListOptions{Query: "status:active", SortKey: model.ProductSortKeysTitle.String(), After: "cursor"})
+		},
+		"Product.Get WithFields": func() {
+			client.Product.Get(ctx, "", WithFields(`id status publishedAt variants(first: 10){ edges{ node{ id sku } } }`))
+		},
+		"Product.Get braced WithFields": func() {
+			client.Product.Get(ctx, "", WithFields(`{ id title }`))
+		},
+		"Product.List WithFields": func() {
+			client.Product.List(ctx, "", WithFields(`id variants{ edges{ node{ id sku } } }`))
+		},
+		"Product.CreateMedia":         func() { client.Product.CreateMedia(ctx, "", nil) },
+		"Product.UpdateMedia":         func() { client.Product.UpdateMedia(ctx, "", nil) },
+		"Product.DeleteMedia":         func() { client.Product.DeleteMedia(ctx, "", nil) },
+		"Product.ReorderMedia":        func() { client.Product.ReorderMedia(ctx, "", nil) },
+		"Product.WaitForMedia":        func() { client.Product.WaitForMedia(ctx, nil, 0) },
+		"Product.ListAll":             func() { client.Product.ListAll(ctx) },
+		"Product.Get":                 func() { client.Product.Get(ctx, "gid://shopify/Product/1") },
+		"Product.Create":              func() { client.Product.Create(ctx, model.ProductInput{}, nil) },
+		"Product.Update":              func() { client.Product.Update(ctx, model.ProductInput{}) },
+		"Product.Delete":              func() { client.Product.Delete(ctx, model.ProductDeleteInput{}) },
+		"Product.VariantsBulkCreate":  func() { client.Product.VariantsBulkCreate(ctx, "", nil) },
+		"Product.VariantsBulkUpdate":  func() { client.Product.VariantsBulkUpdate(ctx, "", nil) },
+		"Product.VariantsBulkReorder": func() { client.Product.VariantsBulkReorder(ctx, "", nil) },
+		"Product.VariantsBulkDelete":  func() { client.Product.VariantsBulkDelete(ctx, "", nil) },
+		"Product.Upsert Handle":       func() { client.Product.Upsert(ctx, ProductUpsertKey{Handle: "shirt"}, ProductUpsert{}) },
+		"Product.Upsert SKU":          func() { client.Product.Upsert(ctx, ProductUpsertKey{SKU: `SKU "1"`}, ProductUpsert{}) },
+		"Product.Sync":                func() { client.Product.Sync(ctx, nil) },
+		"Product.tagsAdd":             func() { client.Product.(*ProductServiceOp).tagsAdd(ctx, "", nil) },
+		"Product.tagsRemove":          func() { client.Product.(*ProductServiceOp).tagsRemove(ctx, "", nil) },
+		"Product.metafieldsSet":       func() { client.Product.(*ProductServiceOp).metafieldsSet(ctx, nil) },
+		"Variant.Update":              func() { client.Variant.Update(ctx, model.ProductVariantInput{}) },
+		"Variant.Get":                 func() { client.Variant.Get(ctx, "") },
+		"Variant.GetBySKU":            func() { client.Variant.GetBySKU(ctx, "SKU-1") },
+		"Variant.ListPage": func() {
+			client.Variant.ListPage(ctx, ListOptions{Query: "product_id:1", SortKey: model.ProductVariantSortKeysSku.String()})
+		},
+		"Variant.Delete":              func() { client.Variant.Delete(ctx, "") },
+		"Inventory.Update":            func() { client.Inventory.Update(ctx, "", model.InventoryItemUpdateInput{}) },
+		"Inventory.Adjust":            func() { client.Inventory.Adjust(ctx, "", nil) },
+		"Inventory.ActivateInventory": func() { client.Inventory.ActivateInventory(ctx, "", "") },
+		"Collection.ListAll":          func() { client.Collection.ListAll(ctx) },
+		"Collection.Get WithFields":   func() { client.Collection.Get(ctx, "", WithFields(`id title productsCount`)) },
+		"Collection.Get":              func() { client.Collection.Get(ctx, "") },
+		"Collection.Create":           func() { client.Collection.Create(ctx, model.CollectionInput{}) },
+		"Collection.Update":           func() { client.Collection.Update(ctx, model.CollectionInput{}) },
+		"Order.Get":                   func() { client.Order.Get(ctx, "") },
+		"Order.List": func() {
+			client.Order.List(ctx, ListOptions{Query: SearchAnd(SearchField("name", `#1001"`), SearchNot(SearchField("tag", "test"))).String()})
+		},
+		"Order.Get WithFields": func() { client.Order.Get(ctx, "", WithFields(`id name displayFulfillmentStatus`)) },
+		"Order.List Fields": func() {
+			client.Order.List(ctx, ListOptions{Fields: `id lineItems{ edges{ node{ id sku } } }`})
+		},
+		"Order.ListAll":                           func() { client.Order.ListAll(ctx) },
+		"Order.ListAfterCursor":                   func() { client.Order.ListAfterCursor(ctx, ListOptions{}) },
+		"Order.Update":                            func() { client.Order.Update(ctx, model.OrderInput{}) },
+		"Fulfillment.Create":                      func() { client.Fulfillment.Create(ctx, model.FulfillmentV2Input{}) },
+		"Location.Get":                            func() { client.Location.Get(ctx, "") },
+		"Metafield.ListAllShopMetafields":         func() { client.Metafield.ListAllShopMetafields(ctx) },
+		"Metafield.ListShopMetafieldsByNamespace": func() { client.Metafield.ListShopMetafieldsByNamespace(ctx, "") },
+		"Metafield.GetShopMetafieldByKey":         func() { client.Metafield.GetShopMetafieldByKey(ctx, "", "") },
+		"Metafield.Delete":                        func() { client.Metafield.Delete(ctx, model.MetafieldDeleteInput{}) },
+		"Webhook.CreateWebhookSubscription": func() {
+			client.Webhook.CreateWebhookSubscription(ctx, "", model.WebhookSubscriptionInput{})
+		},
+		"Webhook.CreateEventBridgeWebhookSubscription": func() {
+			client.Webhook.CreateEventBridgeWebhookSubscription(ctx, "", model.EventBridgeWebhookSubscriptionInput{})
+		},
+		"Webhook.ListWebhookSubscriptions": func() { client.Webhook.ListWebhookSubscriptions(ctx, nil) },
+		"Webhook.DeleteWebhook":            func() { client.Webhook.DeleteWebhook(ctx, "") },
+		"Webhook.UpdateWebhookSubscription": func() {
+			client.Webhook.UpdateWebhookSubscription(ctx, "", model.WebhookSubscriptionInput{})
+		},
+		"Publication.ListPublications":       func() { client.Publication.ListPublications(ctx) },
+		"Publication.ListChannels":           func() { client.Publication.ListChannels(ctx) },
+		"Publication.Publish":                func() { client.Publication.Publish(ctx, "", model.PublicationInput{}) },
+		"Publication.Unpublish":              func() { client.Publication.Unpublish(ctx, "", "") },
+		"Publication.GetProductAvailability": func() { client.Publication.GetProductAvailability(ctx, "") },
+		"BulkOperation.PostBulkQuery":        func() { bulkClient.BulkOperation.PostBulkQuery(ctx, "{ products{ edges{ node{ id } } } }") },
+		"BulkOperation.GetCurrentBulkQuery":  func() { bulkClient.BulkOperation.GetCurrentBulkQuery(ctx) },
+		"BulkOperation.PostBulkMutation":     func() { bulkClient.BulkOperation.PostBulkMutation(ctx, "", "") },
+		"BulkOperation.GetBulkOperation":     func() { bulkClient.BulkOperation.GetBulkOperation(ctx, "") },
+		"BulkOperation.BulkMutation": func() {
+			bulkClient.BulkOperation.BulkMutation(ctx, productCreateMutation, []map[string]interface{}{{"input": model.ProductInput{}}}, nil)
+		},
+		"BulkOperation.getCurrentBulkMutation": func() {
+			bulkClient.BulkOperation.(*BulkOperationServiceOp).getCurrentBulkMutation(ctx)
+		},
+	}
+	var operations []recordedOperation
+	for name, call := range calls {
+		sent, posted := len(gql.operations), len(bulk.queries)
+		call()
+		for _, op := range gql.operations[sent:] {
+			operations = append(operations, recordedOperation{queuedOperation: op, name: name})
+		}
+		for _, query := range bulk.queries[posted:] {
+			op := queuedOperation{op: schema.OperationQuery, document: query}
+			operations = append(operations, recordedOperation{queuedOperation: op, name: name, bulk: true})
+		}
+	}
+
+	// Operations the services only reach after a successful response.
+	operations = append(operations, recordedOperation{name: "BulkOperation.CancelBulkOperation", queuedOperation: queuedOperation{
+		op: schema.OperationMutation, v: &mutationBulkOperationRunQueryCancel{}, variables: map[string]interface{}{"id": graphql.ID("")},
+	}})
+
+	for _, op := range operations {
+		op := op
+		t.Run(op.name, func(t *testing.T) {
+			if op.document != "" {
+				assert.NoError(t, s.ValidateQuery(op.document), op.document)
+				if op.bulk {
+					assert.NoError(t, schema.ValidateBulkQuery(op.document), op.document)
+				}
+			} else {
+				assert.NoError(t, s.ValidateStruct(op.op, op.v, op.variables), fmt.Sprintf("%T", op.v))
+			}
+		})
+	}
+
+	t.Run("webhookSubscriptionMutationSelects", func(t *testing.T) {
+		assert.NoError(t, s.ValidateSelection("WebhookSubscriptionCreatePayload", webhookSubscriptionMutationSelects))
+	})
+}
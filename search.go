@@ -0,0 +1,161 @@
+package shopify
+
+import (
+	"strings"
+	"time"
+)
+
+// SearchQuery is a filter in the Shopify search syntax, the `query` argument of connections such as `products` or
+// `orders`, e.g. `SearchAnd(SearchField("status", "active"), SearchCreatedBetween(from, time.Time{}))`. The values
+// are quoted and escaped, so they can't change the meaning of the query. The zero value matches everything.
+type SearchQuery struct {
+	expr string
+	// grouped is set when the expression must be parenthesized within another one.
+	grouped bool
+}
+
+// String returns the query in the Shopify search syntax, to be passed as ListOptions.Query.
+func (q SearchQuery) String() string {
+	return q.expr
+}
+
+// IsZero reports whether the query has no terms.
+func (q SearchQuery) IsZero() bool {
+	return q.expr == ""
+}
+
+// SearchField matches the objects whose field has the value, e.g. `SearchField("sku", "SHIRT-S")`.
+func SearchField(field, value string) SearchQuery {
+	return SearchQuery{expr: escapeSearchTerm(field) + ":" + quoteSearchValue(value)}
+}
+
+// SearchPrefix matches the objects whose field starts with the prefix, e.g. `title:Shirt*`.
+func SearchPrefix(field, prefix string) SearchQuery {
+	return SearchQuery{expr: escapeSearchTerm(field) + ":" + escapeSearchTerm(prefix) + "*"}
+}
+
+// SearchExists matches the objects with a value for the field.
+func SearchExists(field string) SearchQuery {
+	return SearchQuery{expr: escapeSearchTerm(field) + ":*"}
+}
+
+// SearchText matches the objects with the text in any of their default search fields.
+func SearchText(text string) SearchQuery {
+	return SearchQuery{expr: quoteSearchValue(text)}
+}
+
+// SearchComparator compares the value of a field in a range query.
+type SearchComparator string
+
+const (
+	SearchGreaterThan        SearchComparator = ">"
+	SearchGreaterThanOrEqual SearchComparator = ">="
+	SearchLessThan           SearchComparator = "<"
+	SearchLessThanOrEqual    SearchComparator = "<="
+)
+
+// SearchCompare matches the objects whose field compares to the value, e.g. `inventory_total:>=10`.
+func SearchCompare(field string, comparator SearchComparator, value string) SearchQuery {
+	return SearchQuery{expr: escapeSearchTerm(field) + ":" + string(comparator) + quoteSearchValue(value)}
+}
+
+// SearchTimeRange matches the objects whose time field is within [from, to). A zero bound leaves the range open on
+// that side.
+func SearchTimeRange(field string, from, to time.Time) SearchQuery {
+	var terms []SearchQuery
+	if !from.IsZero() {
+		terms = append(terms, SearchCompare(field, SearchGreaterThanOrEqual, from.UTC().Format(time.RFC3339)))
+	}
+	if !to.IsZero() {
+		terms = append(terms, SearchCompare(field, SearchLessThan, to.UTC().Format(time.RFC3339)))
+	}
+	return SearchAnd(terms...)
+}
+
+// SearchCreatedBetween matches the objects created within [from, to), see SearchTimeRange.
+func SearchCreatedBetween(from, to time.Time) SearchQuery {
+	return SearchTimeRange("created_at", from, to)
+}
+
+// SearchUpdatedBetween matches the objects updated within [from, to), see SearchTimeRange.
+func SearchUpdatedBetween(from, to time.Time) SearchQuery {
+	return SearchTimeRange("updated_at", from, to)
+}
+
+// SearchAnd matches the objects matching all the queries. Zero queries are skipped.
+func SearchAnd(queries ...SearchQuery) SearchQuery {
+	return joinSearchQueries(" AND ", queries)
+}
+
+// SearchOr matches the objects matching any of the queries. Zero queries are skipped.
+func SearchOr(queries ...SearchQuery) SearchQuery {
+	return joinSearchQueries(" OR ", queries)
+}
+
+// SearchNot matches the objects not matching the query.
+func SearchNot(query SearchQuery) SearchQuery {
+	if query.IsZero() {
+		return query
+	}
+	return SearchQuery{expr: "NOT " + query.group()}
+}
+
+func joinSearchQueries(operator string, queries []SearchQuery) SearchQuery {
+	var terms []string
+	var last SearchQuery
+	for _, q := range queries {
+		if !q.IsZero() {
+			terms = append(terms, q.group())
+			last = q
+		}
+	}
+	if len(terms) <= 1 {
+		return last
+	}
+	return SearchQuery{expr: strings.Join(terms, operator), grouped: true}
+}
+
+// group parenthesizes the compound query, so it's read as a whole within another one.
+func (q SearchQuery) group() string {
+	if q.grouped {
+		return "(" + q.expr + ")"
+	}
+	return q.expr
+}
+
+// quoteSearchValue quotes the value, escaping the quotes and backslashes within it.
+func quoteSearchValue(value string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range value {
+		if r == '"' || r == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// escapeSearchTerm escapes the characters of the search syntax in an unquoted term, such as a field name.
+func escapeSearchTerm(term string) string {
+	var sb strings.Builder
+	for _, r := range term {
+		switch r {
+		case '\\', ':', '(', ')', '"', '\'', '*', ' ', '\t', '\n', '\r':
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// searchArgument returns the `query` argument of a connection filtered by the search query as a GraphQL string
+// value, or nothing when the query is empty. Bulk queries can't declare variables, so the query is written into
+// the document, escaped.
+func searchArgument(query string) string {
+	if query == "" {
+		return ""
+	}
+	return "(query: " + graphQLString(query) + ")"
+}
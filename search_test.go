@@ -0,0 +1,79 @@
+package shopify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchQuery(t *testing.T) {
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 2, 1, 5, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	tests := []struct {
+		name  string
+		query SearchQuery
+		want  string
+	}{{
+		name:  "field",
+		query: SearchField("title", `Shirt "XL" \ red`),
+		want:  `title:"Shirt \"XL\" \\ red"`,
+	}, {
+		name:  "injected field name",
+		query: SearchField("title:x OR id", "1"),
+		want:  `title\:x\ OR\ id:"1"`,
+	}, {
+		name:  "prefix",
+		query: SearchPrefix("sku", "SHIRT (S)"),
+		want:  `sku:SHIRT\ \(S\)*`,
+	}, {
+		name:  "exists",
+		query: SearchExists("barcode"),
+		want:  `barcode:*`,
+	}, {
+		name:  "text",
+		query: SearchText(`"quoted"`),
+		want:  `"\"quoted\""`,
+	}, {
+		name:  "compare",
+		query: SearchCompare("inventory_total", SearchGreaterThanOrEqual, "10"),
+		want:  `inventory_total:>="10"`,
+	}, {
+		name:  "created between",
+		query: SearchCreatedBetween(from, to),
+		want:  `created_at:>="2023-01-01T00:00:00Z" AND created_at:<"2023-02-01T10:00:00Z"`,
+	}, {
+		name:  "updated after",
+		query: SearchUpdatedBetween(from, time.Time{}),
+		want:  `updated_at:>="2023-01-01T00:00:00Z"`,
+	}, {
+		name: "nested",
+		query: SearchAnd(
+			SearchField("status", "active"),
+			SearchOr(SearchField("vendor", "A"), SearchField("vendor", "B")),
+			SearchNot(SearchAnd(SearchField("tag", "sale"), SearchField("tag", "old"))),
+			SearchQuery{},
+		),
+		want: `status:"active" AND (vendor:"A" OR vendor:"B") AND NOT (tag:"sale" AND tag:"old")`,
+	}, {
+		name:  "single term",
+		query: SearchOr(SearchQuery{}, SearchField("status", "draft")),
+		want:  `status:"draft"`,
+	}, {
+		name:  "empty",
+		query: SearchAnd(SearchNot(SearchQuery{}), SearchTimeRange("created_at", time.Time{}, time.Time{})),
+		want:  ``,
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.query.String())
+		})
+	}
+}
+
+func TestSearchArgument(t *testing.T) {
+	assert.Equal(t, "", searchArgument(""))
+	assert.Equal(t, `(query: "title:\"x\\\") { id } #\"")`, searchArgument(`title:"x\") { id } #"`))
+}
@@ -0,0 +1,114 @@
+package shopify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/sogko/go-shopify-graphql/model"
+)
+
+type mutationStagedUploadsCreate struct {
+	StagedUploadsCreateResult struct {
+		StagedTargets []model.StagedMediaUploadTarget `json:"stagedTargets,omitempty"`
+		UserErrors    []model.UserError               `json:"userErrors,omitempty"`
+	} `graphql:"stagedUploadsCreate(input: $input)" json:"stagedUploadsCreate"`
+}
+
+// stagedUploadPathParameter is the form parameter of a staged target holding the path of the uploaded file.
+const stagedUploadPathParameter = "key"
+
+// createStagedUpload requests a target to upload a file to before passing it to a mutation.
+func (c *Client) createStagedUpload(ctx context.Context, input model.StagedUploadInput) (*model.StagedMediaUploadTarget, error) {
+	m := mutationStagedUploadsCreate{}
+	vars := map[string]interface{}{
+		"input": []model.StagedUploadInput{input},
+	}
+
+	err := c.Mutate(ctx, &m, vars)
+	if err != nil {
+		return nil, fmt.Errorf("mutation: %w", err)
+	}
+	if len(m.StagedUploadsCreateResult.UserErrors) > 0 {
+		return nil, fmt.Errorf("%+v", m.StagedUploadsCreateResult.UserErrors)
+	}
+	if len(m.StagedUploadsCreateResult.StagedTargets) == 0 {
+		return nil, fmt.Errorf("no staged upload target returned")
+	}
+
+	return &m.StagedUploadsCreateResult.StagedTargets[0], nil
+}
+
+// uploadToStagedTarget posts the file as a multipart form with the parameters of the staged target. The file is
+// streamed between the form parts, with a Content-Length when its size is known, i.e. not negative.
+func (c *Client) uploadToStagedTarget(ctx context.Context, target *model.StagedMediaUploadTarget, filename string, file io.Reader, size int64) error {
+	if target.URL == nil {
+		return fmt.Errorf("staged upload target has no URL")
+	}
+
+	// The parts before and after the file are written apart so the file isn't buffered.
+	var head, tail bytes.Buffer
+	section := &switchWriter{w: &head}
+	w := multipart.NewWriter(section)
+	for _, p := range target.Parameters {
+		err := w.WriteField(p.Name, p.Value)
+		if err != nil {
+			return fmt.Errorf("write form field: %w", err)
+		}
+	}
+	_, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		return fmt.Errorf("create form file: %w", err)
+	}
+	section.w = &tail
+	err = w.Close()
+	if err != nil {
+		return fmt.Errorf("close form: %w", err)
+	}
+
+	body := io.MultiReader(&head, file, &tail)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, *target.URL, body)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	if size >= 0 {
+		req.ContentLength = int64(head.Len()) + size + int64(tail.Len())
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	// The staged target is authenticated by its parameters, the shop credentials must not be sent along.
+	httpClient := &http.Client{Transport: c.transport}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upload: unexpected status %s: %s", resp.Status, msg)
+	}
+
+	return nil
+}
+
+type switchWriter struct {
+	w io.Writer
+}
+
+func (s *switchWriter) Write(p []byte) (int, error) {
+	return s.w.Write(p)
+}
+
+// stagedUploadPath returns the path of a file uploaded to the staged target.
+func stagedUploadPath(target *model.StagedMediaUploadTarget) (string, error) {
+	for _, p := range target.Parameters {
+		if p.Name == stagedUploadPathParameter {
+			return p.Value, nil
+		}
+	}
+	return "", fmt.Errorf("staged upload target has no `%s` parameter", stagedUploadPathParameter)
+}
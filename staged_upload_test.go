@@ -0,0 +1,50 @@
+package shopify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadToStagedTarget(t *testing.T) {
+	var contentLengths []int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentLengths = append(contentLengths, r.ContentLength)
+		assert.Empty(t, r.Header.Get("X-Shopify-Access-Token"))
+
+		err := r.ParseMultipartForm(1 << 20)
+		require.NoError(t, err)
+		assert.Equal(t, "tmp/1/bulk_op_vars.jsonl", r.FormValue("key"))
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		b, _ := io.ReadAll(file)
+		assert.Equal(t, "bulk_op_vars.jsonl", header.Filename)
+		assert.Equal(t, "{\"input\":{}}\n", string(b))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test", WithToken("token"))
+	target := &model.StagedMediaUploadTarget{
+		URL:        &srv.URL,
+		Parameters: []model.StagedUploadParameter{{Name: "key", Value: "tmp/1/bulk_op_vars.jsonl"}},
+	}
+	file := "{\"input\":{}}\n"
+
+	err := client.uploadToStagedTarget(context.Background(), target, "bulk_op_vars.jsonl", strings.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	// The file is streamed, so an unknown size is sent chunked.
+	err = client.uploadToStagedTarget(context.Background(), target, "bulk_op_vars.jsonl", io.MultiReader(strings.NewReader(file)), -1)
+	require.NoError(t, err)
+
+	require.Len(t, contentLengths, 2)
+	assert.Greater(t, contentLengths[0], int64(len(file)))
+	assert.Equal(t, int64(-1), contentLengths[1])
+}
@@ -3,13 +3,26 @@ package shopify
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/vinhluan/go-graphql-client"
 )
 
 //go:generate mockgen -destination=./mock/variant_service.go -package=mock . VariantService
 type VariantService interface {
+	// Get returns the variant with its inventory item and the inventory levels of the item at each location.
+	Get(ctx context.Context, id string, opts ...QueryOption) (*model.ProductVariant, error)
+	// GetBySKU returns the variant with the SKU, nil when there's none, or ErrAmbiguousVariant when several variants
+	// have the SKU.
+	GetBySKU(ctx context.Context, sku string, opts ...QueryOption) (*model.ProductVariant, error)
+	// GetByBarcode returns the variant with the barcode like GetBySKU.
+	GetByBarcode(ctx context.Context, barcode string, opts ...QueryOption) (*model.ProductVariant, error)
+	// ListPage returns a page of the variants of the shop, e.g. those of a product with the `product_id:<id>` query.
+	ListPage(ctx context.Context, opts ListOptions) ([]*model.ProductVariant, *model.PageInfo, error)
+
 	Update(ctx context.Context, variant model.ProductVariantInput) error
+	Delete(ctx context.Context, id string) error
 }
 
 type VariantServiceOp struct {
@@ -18,12 +31,228 @@ type VariantServiceOp struct {
 
 var _ VariantService = &VariantServiceOp{}
 
+var variantType = reflect.TypeOf(model.ProductVariant{})
+
+// variantLookupPageSize is the number of variants fetched per page to check that a SKU or barcode matches a single
+// one.
+const variantLookupPageSize = 250
+
 type mutationProductVariantUpdate struct {
 	ProductVariantUpdateResult struct {
 		UserErrors []model.UserError `json:"userErrors,omitempty"`
 	} `graphql:"productVariantUpdate(input: $input)" json:"productVariantUpdate"`
 }
 
+type mutationProductVariantDelete struct {
+	ProductVariantDeleteResult struct {
+		UserErrors []model.UserError `json:"userErrors,omitempty"`
+	} `graphql:"productVariantDelete(id: $id)" json:"productVariantDelete"`
+}
+
+const variantBaseQuery = `
+	id
+	legacyResourceId
+	title
+	displayName
+	sku
+	barcode
+	selectedOptions{
+		name
+		value
+	}
+	position
+	compareAtPrice
+	price
+	taxable
+	inventoryPolicy
+	inventoryQuantity
+	availableForSale
+	product{
+		id
+		handle
+	}
+`
+
+var variantQuery = fmt.Sprintf(`
+	%s
+	inventoryItem{
+		id
+		legacyResourceId
+		tracked
+		inventoryLevels(first:250){
+			edges{
+				node{
+					id
+					available
+					location{
+						id
+						name
+					}
+				}
+			}
+		}
+	}
+`, variantBaseQuery)
+
+func (s *VariantServiceOp) Get(ctx context.Context, id string, opts ...QueryOption) (*model.ProductVariant, error) {
+	fields, err := selectFields(variantType, newQueryOptions(opts).fields, variantQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	q := fmt.Sprintf(`
+		query productVariant($id: ID!) {
+			productVariant(id: $id){
+				%s
+			}
+		}
+	`, fields)
+
+	vars := map[string]interface{}{
+		"id": id,
+	}
+
+	out := struct {
+		ProductVariant *model.ProductVariant `json:"productVariant"`
+	}{}
+	err = s.client.QueryString(ctx, q, vars, &out)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return out.ProductVariant, nil
+}
+
+func (s *VariantServiceOp) GetBySKU(ctx context.Context, sku string, opts ...QueryOption) (*model.ProductVariant, error) {
+	return s.getByField(ctx, "sku", sku, func(v *model.ProductVariant) *string { return v.Sku }, opts)
+}
+
+func (s *VariantServiceOp) GetByBarcode(ctx context.Context, barcode string, opts ...QueryOption) (*model.ProductVariant, error) {
+	return s.getByField(ctx, "barcode", barcode, func(v *model.ProductVariant) *string { return v.Barcode }, opts)
+}
+
+// getByField searches the variants by the field, keeps those whose value is exactly the searched one, as the search
+// also matches partially, and gets the only one left.
+func (s *VariantServiceOp) getByField(ctx context.Context, field, value string, valueOf func(*model.ProductVariant) *string, opts []QueryOption) (*model.ProductVariant, error) {
+	if value == "" {
+		return nil, fmt.Errorf("the %s must be set", field)
+	}
+
+	q := fmt.Sprintf(`
+		query productVariants($query: String!, $cursor: String) {
+			productVariants(first:%d, query: $query, after: $cursor){
+				nodes{
+					id
+					sku
+					barcode
+				}
+				pageInfo{
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	`, variantLookupPageSize)
+
+	vars := map[string]interface{}{
+		"query": SearchField(field, value).String(),
+	}
+
+	// The exact match can be on any page of the partial matches, so every page is checked.
+	var id string
+	for {
+		out := struct {
+			ProductVariants *model.ProductVariantConnection `json:"productVariants"`
+		}{}
+		err := s.client.QueryString(ctx, q, vars, &out)
+		if err != nil {
+			return nil, fmt.Errorf("query: %w", err)
+		}
+		if out.ProductVariants == nil {
+			break
+		}
+
+		for i := range out.ProductVariants.Nodes {
+			v := valueOf(&out.ProductVariants.Nodes[i])
+			if v == nil || *v != value {
+				continue
+			}
+			if id != "" {
+				return nil, fmt.Errorf("%w: %s `%s`", ErrAmbiguousVariant, field, value)
+			}
+			id = out.ProductVariants.Nodes[i].ID
+		}
+
+		cursor, ok := nextPageCursor(out.ProductVariants.PageInfo)
+		if !ok {
+			break
+		}
+		vars["cursor"] = cursor
+	}
+	if id == "" {
+		return nil, nil
+	}
+
+	return s.Get(ctx, id, opts...)
+}
+
+func (s *VariantServiceOp) ListPage(ctx context.Context, opts ListOptions) ([]*model.ProductVariant, *model.PageInfo, error) {
+	fields, err := selectFields(variantType, opts.Fields, variantBaseQuery)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q := fmt.Sprintf(`
+		query productVariants($query: String, $first: Int, $last: Int, $before: String, $after: String, $reverse: Boolean, $sortKey: ProductVariantSortKeys) {
+			productVariants(query: $query, first: $first, last: $last, before: $before, after: $after, reverse: $reverse, sortKey: $sortKey){
+				edges{
+					node{
+						%s
+					}
+				}
+				pageInfo{
+					hasNextPage
+					hasPreviousPage
+					startCursor
+					endCursor
+				}
+			}
+		}
+	`, fields)
+
+	vars, err := listPageVariables(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.SortKey != "" {
+		sortKey := model.ProductVariantSortKeys(opts.SortKey)
+		if !sortKey.IsValid() {
+			return nil, nil, fmt.Errorf("invalid product variant sort key `%s`", opts.SortKey)
+		}
+		vars["sortKey"] = sortKey
+	}
+
+	out := struct {
+		ProductVariants struct {
+			Edges []struct {
+				Node *model.ProductVariant `json:"node,omitempty"`
+			} `json:"edges,omitempty"`
+			PageInfo model.PageInfo `json:"pageInfo"`
+		} `json:"productVariants"`
+	}{}
+	err = s.client.QueryString(ctx, q, vars, &out)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query: %w", err)
+	}
+
+	res := make([]*model.ProductVariant, 0, len(out.ProductVariants.Edges))
+	for _, edge := range out.ProductVariants.Edges {
+		res = append(res, edge.Node)
+	}
+
+	return res, &out.ProductVariants.PageInfo, nil
+}
+
 func (s *VariantServiceOp) Update(ctx context.Context, variant model.ProductVariantInput) error {
 	m := mutationProductVariantUpdate{}
 
@@ -41,3 +270,21 @@ func (s *VariantServiceOp) Update(ctx context.Context, variant model.ProductVari
 
 	return nil
 }
+
+func (s *VariantServiceOp) Delete(ctx context.Context, id string) error {
+	m := mutationProductVariantDelete{}
+
+	vars := map[string]interface{}{
+		"id": graphql.ID(id),
+	}
+	err := s.client.Mutate(ctx, &m, vars)
+	if err != nil {
+		return fmt.Errorf("mutation: %w", err)
+	}
+
+	if len(m.ProductVariantDeleteResult.UserErrors) > 0 {
+		return fmt.Errorf("%+v", m.ProductVariantDeleteResult.UserErrors)
+	}
+
+	return nil
+}
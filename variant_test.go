@@ -0,0 +1,107 @@
+package shopify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariantGet(t *testing.T) {
+	gql := &queuedGraphQL{responses: map[string][]string{"productVariant": {
+		`{"id":"gid://shopify/ProductVariant/1","sku":"S-1","inventoryItem":{"id":"gid://shopify/InventoryItem/1","inventoryLevels":{"edges":[
+			{"node":{"id":"gid://shopify/InventoryLevel/1","available":3,"location":{"id":"gid://shopify/Location/1","name":"Warehouse"}}}
+		]}}}`,
+	}}}
+	client := NewClient("test", WithGraphQLClient(gql))
+
+	variant, err := client.Variant.Get(context.Background(), "gid://shopify/ProductVariant/1")
+	require.NoError(t, err)
+	assert.Equal(t, "S-1", *variant.Sku)
+	levels := variant.InventoryItem.InventoryLevels.Edges
+	require.Len(t, levels, 1)
+	assert.Equal(t, 3, levels[0].Node.Available)
+	assert.Equal(t, "Warehouse", levels[0].Node.Location.Name)
+}
+
+func TestVariantGetBySKU(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("exact match", func(t *testing.T) {
+		gql := &queuedGraphQL{responses: map[string][]string{
+			"productVariants": {`{"nodes":[
+				{"id":"gid://shopify/ProductVariant/2","sku":"S-10"},
+				{"id":"gid://shopify/ProductVariant/1","sku":"S-1"}
+			]}`},
+			"productVariant": {`{"id":"gid://shopify/ProductVariant/1","sku":"S-1"}`},
+		}}
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		variant, err := client.Variant.GetBySKU(ctx, "S-1")
+		require.NoError(t, err)
+		assert.Equal(t, "gid://shopify/ProductVariant/1", variant.ID)
+		assert.Equal(t, `sku:"S-1"`, gql.variables("productVariants")[0]["query"])
+		assert.Equal(t, "gid://shopify/ProductVariant/1", gql.variables("productVariant")[0]["id"])
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		gql := &queuedGraphQL{responses: map[string][]string{"productVariants": {
+			`{"nodes":[
+				{"id":"gid://shopify/ProductVariant/1","barcode":"123"},
+				{"id":"gid://shopify/ProductVariant/2","barcode":"123"}
+			]}`,
+		}}}
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		_, err := client.Variant.GetByBarcode(ctx, "123")
+		assert.ErrorIs(t, err, ErrAmbiguousVariant)
+		assert.EqualError(t, err, "several variants match: barcode `123`")
+	})
+
+	t.Run("exact match on a later page", func(t *testing.T) {
+		gql := &queuedGraphQL{responses: map[string][]string{
+			"productVariants": {
+				`{"nodes":[{"id":"gid://shopify/ProductVariant/2","sku":"S-10"}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}`,
+				`{"nodes":[{"id":"gid://shopify/ProductVariant/1","sku":"S-1"}],"pageInfo":{"hasNextPage":false}}`,
+			},
+			"productVariant": {`{"id":"gid://shopify/ProductVariant/1","sku":"S-1"}`},
+		}}
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		variant, err := client.Variant.GetBySKU(ctx, "S-1")
+		require.NoError(t, err)
+		assert.Equal(t, "gid://shopify/ProductVariant/1", variant.ID)
+		assert.Nil(t, gql.variables("productVariants")[0]["cursor"])
+		assert.Equal(t, "c1", gql.variables("productVariants")[1]["cursor"])
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		gql := &queuedGraphQL{responses: map[string][]string{"productVariants": {
+			`{"nodes":[{"id":"gid://shopify/ProductVariant/2","sku":"S-10"}]}`,
+		}}}
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		variant, err := client.Variant.GetBySKU(ctx, "S-1")
+		require.NoError(t, err)
+		assert.Nil(t, variant)
+	})
+}
+
+func TestVariantListPage(t *testing.T) {
+	gql := &queuedGraphQL{responses: map[string][]string{"productVariants": {
+		`{"edges":[{"node":{"id":"gid://shopify/ProductVariant/1"}}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}`,
+	}}}
+	client := NewClient("test", WithGraphQLClient(gql))
+
+	variants, pageInfo, err := client.Variant.ListPage(context.Background(), ListOptions{Query: "product_id:1", SortKey: model.ProductVariantSortKeysSku.String()})
+	require.NoError(t, err)
+	require.Len(t, variants, 1)
+	assert.True(t, pageInfo.HasNextPage)
+	assert.Equal(t, model.ProductVariantSortKeysSku, gql.variables("productVariants")[0]["sortKey"])
+	assert.Equal(t, defaultListPageSize, gql.variables("productVariants")[0]["first"])
+
+	_, _, err = client.Variant.ListPage(context.Background(), ListOptions{SortKey: "PRICE"})
+	assert.EqualError(t, err, "invalid product variant sort key `PRICE`")
+}
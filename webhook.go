@@ -22,11 +22,11 @@ type WebhookServiceOp struct {
 var _ WebhookService = &WebhookServiceOp{}
 
 type mutationWebhookCreate struct {
-	WebhookCreateResult *model.WebhookSubscriptionCreatePayload `graphql:"webhookSubscriptionCreate(topic: $topic, webhookSubscription: $webhookSubscription)" json:"webhookSubscriptionCreate"`
+	WebhookCreateResult *model.WebhookSubscriptionCreatePayload `json:"webhookSubscriptionCreate"`
 }
 
 type mutationWebhookUpdate struct {
-	WebhookUpdateResult *model.WebhookSubscriptionUpdatePayload `graphql:"webhookSubscriptionUpdate(id: $id, webhookSubscription: $webhookSubscription)" json:"webhookSubscriptionUpdate"`
+	WebhookUpdateResult *model.WebhookSubscriptionUpdatePayload `json:"webhookSubscriptionUpdate"`
 }
 
 type mutationWebhookDelete struct {
@@ -34,7 +34,7 @@ type mutationWebhookDelete struct {
 }
 
 type mutationEventBridgeWebhookCreate struct {
-	EventBridgeWebhookCreateResult *model.EventBridgeWebhookSubscriptionCreatePayload `graphql:"eventBridgeWebhookSubscriptionCreate(topic: $topic, webhookSubscription: $webhookSubscription)" json:"eventBridgeWebhookSubscriptionCreate"`
+	EventBridgeWebhookCreateResult *model.EventBridgeWebhookSubscriptionCreatePayload `json:"eventBridgeWebhookSubscriptionCreate"`
 }
 
 // NOTE: Have to use this because writeQuery function will not write structs that implements UnmarshalJSON function
@@ -70,13 +70,34 @@ webhookSubscription {
 	}
 }`
 
+var webhookSubscriptionCreateMutation = fmt.Sprintf(`
+mutation webhookSubscriptionCreate($topic: WebhookSubscriptionTopic!, $webhookSubscription: WebhookSubscriptionInput!) {
+	webhookSubscriptionCreate(topic: $topic, webhookSubscription: $webhookSubscription) {
+		%s
+	}
+}`, webhookSubscriptionMutationSelects)
+
+var eventBridgeWebhookSubscriptionCreateMutation = fmt.Sprintf(`
+mutation eventBridgeWebhookSubscriptionCreate($topic: WebhookSubscriptionTopic!, $webhookSubscription: EventBridgeWebhookSubscriptionInput!) {
+	eventBridgeWebhookSubscriptionCreate(topic: $topic, webhookSubscription: $webhookSubscription) {
+		%s
+	}
+}`, webhookSubscriptionMutationSelects)
+
+var webhookSubscriptionUpdateMutation = fmt.Sprintf(`
+mutation webhookSubscriptionUpdate($id: ID!, $webhookSubscription: WebhookSubscriptionInput!) {
+	webhookSubscriptionUpdate(id: $id, webhookSubscription: $webhookSubscription) {
+		%s
+	}
+}`, webhookSubscriptionMutationSelects)
+
 func (s WebhookServiceOp) CreateWebhookSubscription(ctx context.Context, topic model.WebhookSubscriptionTopic, input model.WebhookSubscriptionInput) (*model.WebhookSubscription, error) {
 	m := mutationWebhookCreate{}
 	vars := map[string]interface{}{
 		"topic":               topic,
 		"webhookSubscription": input,
 	}
-	err := s.client.Mutate(ctx, &m, vars)
+	err := s.client.MutateString(ctx, webhookSubscriptionCreateMutation, vars, &m)
 	if err != nil {
 		return nil, fmt.Errorf("mutation: %w", err)
 	}
@@ -95,7 +116,7 @@ func (s WebhookServiceOp) CreateEventBridgeWebhookSubscription(ctx context.Conte
 		"webhookSubscription": input,
 	}
 
-	err := s.client.Mutate(ctx, &m, vars)
+	err := s.client.MutateString(ctx, eventBridgeWebhookSubscriptionCreateMutation, vars, &m)
 	if err != nil {
 		return nil, fmt.Errorf("mutation: %w", err)
 	}
@@ -200,7 +221,7 @@ func (s WebhookServiceOp) UpdateWebhookSubscription(ctx context.Context, webhook
 		"id":                  webhookID,
 		"webhookSubscription": input,
 	}
-	err := s.client.Mutate(ctx, &m, vars)
+	err := s.client.MutateString(ctx, webhookSubscriptionUpdateMutation, vars, &m)
 	if err != nil {
 		return nil, fmt.Errorf("mutation: %w", err)
 	}
@@ -0,0 +1,66 @@
+package shopify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sogko/go-shopify-graphql/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSubscriptionMutations(t *testing.T) {
+	ctx := context.Background()
+	subscription := `{"webhookSubscription":{"id":"gid://shopify/WebhookSubscription/1","topic":"PRODUCTS_UPDATE",
+		"endpoint":{"__typename":"WebhookHttpEndpoint","callbackUrl":"https://example.com/hook"}}}`
+	callbackURL := "https://example.com/hook"
+	input := model.WebhookSubscriptionInput{CallbackURL: &callbackURL}
+
+	t.Run("create", func(t *testing.T) {
+		gql := &queuedGraphQL{responses: map[string][]string{"webhookSubscriptionCreate": {subscription}}}
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		sub, err := client.Webhook.CreateWebhookSubscription(ctx, model.WebhookSubscriptionTopicProductsUpdate, input)
+		require.NoError(t, err)
+		assert.Equal(t, "gid://shopify/WebhookSubscription/1", sub.ID)
+		assert.Equal(t, &model.WebhookHTTPEndpoint{CallbackURL: "https://example.com/hook"}, sub.Endpoint)
+		assert.Equal(t, webhookSubscriptionCreateMutation, gql.operations[0].document)
+		assert.Equal(t, model.WebhookSubscriptionTopicProductsUpdate, gql.operations[0].variables["topic"])
+		assert.Equal(t, input, gql.operations[0].variables["webhookSubscription"])
+	})
+
+	t.Run("create on EventBridge", func(t *testing.T) {
+		gql := &queuedGraphQL{responses: map[string][]string{"eventBridgeWebhookSubscriptionCreate": {
+			`{"webhookSubscription":{"id":"gid://shopify/WebhookSubscription/2",
+				"endpoint":{"__typename":"WebhookEventBridgeEndpoint","arn":"arn:aws:events:us-east-1::event-source/aws.partner/shopify.com/1/source"}}}`,
+		}}}
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		arn := "arn:aws:events:us-east-1::event-source/aws.partner/shopify.com/1/source"
+		sub, err := client.Webhook.CreateEventBridgeWebhookSubscription(ctx, model.WebhookSubscriptionTopicProductsUpdate, model.EventBridgeWebhookSubscriptionInput{Arn: &arn})
+		require.NoError(t, err)
+		assert.Equal(t, &model.WebhookEventBridgeEndpoint{Arn: arn}, sub.Endpoint)
+		assert.Equal(t, eventBridgeWebhookSubscriptionCreateMutation, gql.operations[0].document)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		gql := &queuedGraphQL{responses: map[string][]string{"webhookSubscriptionUpdate": {subscription}}}
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		sub, err := client.Webhook.UpdateWebhookSubscription(ctx, "gid://shopify/WebhookSubscription/1", input)
+		require.NoError(t, err)
+		assert.Equal(t, model.WebhookSubscriptionTopicProductsUpdate, sub.Topic)
+		assert.Equal(t, webhookSubscriptionUpdateMutation, gql.operations[0].document)
+		assert.Equal(t, "gid://shopify/WebhookSubscription/1", gql.operations[0].variables["id"])
+	})
+
+	t.Run("user errors", func(t *testing.T) {
+		gql := &queuedGraphQL{responses: map[string][]string{"webhookSubscriptionCreate": {
+			`{"userErrors":[{"field":["webhookSubscription","callbackUrl"],"message":"Address is invalid"}]}`,
+		}}}
+		client := NewClient("test", WithGraphQLClient(gql))
+
+		_, err := client.Webhook.CreateWebhookSubscription(ctx, model.WebhookSubscriptionTopicProductsUpdate, input)
+		assert.ErrorContains(t, err, "Address is invalid")
+	})
+}